@@ -73,6 +73,7 @@ type StandardDeclaration struct {
 	Identifier *IdentifierExpression
 	Block      *BlockStatement
 	Visibility Visibility
+	Doc        string // text of the preceding `///` comments
 }
 
 type TypeStatement struct {
@@ -82,6 +83,7 @@ type TypeStatement struct {
 	GenericParams *GenericParametersClause
 	Value         TypeExpression
 	Visibility    Visibility
+	Doc           string // text of the preceding `///` comments
 }
 
 type ExtensionDeclaration struct {
@@ -119,11 +121,13 @@ type BlockStatement struct {
 
 type VariableStatement struct {
 	KeyWPos    token.TokenPosition
-	Identifier *IdentifierExpression
+	Identifier *IdentifierExpression   // nil when the statement destructures a tuple
+	Elements   []*IdentifierExpression // the names bound by destructuring a tuple, `let (a, b) = f();`
 	Value      Expression
 	IsConstant bool
 	IsGlobal   bool
 	Visibility Visibility
+	Doc        string // text of the preceding `///` comments
 }
 
 type FunctionStatement struct {
@@ -134,7 +138,7 @@ type IfStatement struct {
 	KeyWPos     token.TokenPosition
 	Condition   Expression
 	Action      *BlockStatement
-	Alternative *BlockStatement
+	Alternative Statement // either a *BlockStatement or the *IfStatement of an `else if`
 }
 
 type ReturnStatement struct {
@@ -148,6 +152,24 @@ type WhileStatement struct {
 	Action    *BlockStatement
 }
 
+// A C-style loop, `for let i = 0; i < n; i += 1 { }`, each clause may be omitted
+type ForStatement struct {
+	KeyWPos   token.TokenPosition
+	Init      Statement
+	Condition Expression
+	Post      Expression
+	Action    *BlockStatement
+}
+
+// A loop over the elements of a collection, `for x in collection { }`
+type ForInStatement struct {
+	KeyWPos    token.TokenPosition
+	Element    *IdentifierExpression
+	InPos      token.TokenPosition
+	Collection Expression
+	Action     *BlockStatement
+}
+
 type ExpressionStatement struct {
 	Expr Expression
 }
@@ -160,6 +182,7 @@ type StructStatement struct {
 	RBracePos     token.TokenPosition
 	Fields        []*StructField
 	Visibility    Visibility
+	Doc           string // text of the preceding `///` comments
 }
 
 type DereferenceAssignmentStatement struct {
@@ -170,6 +193,7 @@ type DereferenceAssignmentStatement struct {
 type StructField struct {
 	Identifier *IdentifierExpression
 	Visibility Visibility
+	Doc        string // text of the preceding `///` comments
 }
 
 type EnumStatement struct {
@@ -180,12 +204,14 @@ type EnumStatement struct {
 	Variants      []*EnumVariantExpression
 	RBracePos     token.TokenPosition
 	Visibility    Visibility
+	Doc           string // text of the preceding `///` comments
 }
 
 type EnumVariantExpression struct {
 	Identifier   *IdentifierExpression
 	Fields       *FieldListExpression
 	Discriminant *EnumDiscriminantExpression
+	Doc          string // text of the preceding `///` comments
 }
 
 type FieldListExpression struct {
@@ -217,6 +243,19 @@ type SwitchCaseExpression struct {
 
 type BreakStatement struct {
 	KeyWPos token.TokenPosition
+	Label   *IdentifierExpression // the label of the loop or switch to exit, nil for the innermost
+}
+
+type ContinueStatement struct {
+	KeyWPos token.TokenPosition
+	Label   *IdentifierExpression // the label of the loop to continue, nil for the innermost
+}
+
+// A loop or switch named for labeled `break` & `continue` statements, `outer: for x in xs { }`
+type LabeledStatement struct {
+	Label    *IdentifierExpression
+	ColonPos token.TokenPosition
+	Stmt     Statement
 }
 
 // * Expressions
@@ -258,6 +297,7 @@ type CallExpression struct {
 	LParenPos         token.TokenPosition
 	RParenPos         token.TokenPosition
 	SwitchExpansionOf Expression
+	PipePos           token.TokenPosition // the `|>` of a piped call, `x |> f(y)`, the piped value is the first argument
 }
 
 type CallArgument struct {
@@ -279,6 +319,15 @@ type FieldAccessExpression struct {
 	DotPos token.TokenPosition
 }
 
+// A conditional expression, `if c { a } else { b }`
+type IfExpression struct {
+	KeyWPos     token.TokenPosition
+	Condition   Expression
+	Action      Expression
+	Alternative Expression // the *IfExpression of an `else if` chain nests
+	RBracePos   token.TokenPosition
+}
+
 type KeyValueExpression struct {
 	Key      Expression
 	Value    Expression
@@ -311,6 +360,7 @@ type FunctionExpression struct {
 	IsStatic      bool
 	IsMutating    bool
 	Visibility    Visibility
+	Doc           string // text of the preceding `///` comments
 }
 
 type FunctionParameter struct {
@@ -328,13 +378,15 @@ type AnnotatedIdentifier struct {
 
 // * Literals
 type IntegerLiteral struct {
-	Pos   token.TokenPosition
-	Value int64
+	Pos    token.TokenPosition
+	Value  uint64 // literals are unsigned, negation is a prefix operator
+	Suffix string // the type suffix of the literal, e.g. `u8` in `10u8`
 }
 
 type FloatLiteral struct {
-	Pos   token.TokenPosition
-	Value float64
+	Pos    token.TokenPosition
+	Value  float64
+	Suffix string // the type suffix of the literal, e.g. `f` in `3.0f`
 }
 
 type StringLiteral struct {
@@ -342,6 +394,13 @@ type StringLiteral struct {
 	Value string
 }
 
+// A string literal with interpolated values, `"hello \(name)"`
+type InterpolatedStringLiteral struct {
+	Start token.TokenPosition
+	Parts []Expression // the segments of the string as *StringLiteral & the interpolated expressions, in source order
+	End   token.TokenPosition
+}
+
 type CharLiteral struct {
 	Pos   token.TokenPosition
 	Value int64
@@ -366,6 +425,13 @@ type ArrayLiteral struct {
 	RBracketPos token.TokenPosition
 }
 
+// A tuple of two or more elements, `(10, true)`
+type TupleLiteral struct {
+	LParenPos token.TokenPosition
+	Elements  []Expression
+	RParenPos token.TokenPosition
+}
+
 type MapLiteral struct {
 	LBracePos token.TokenPosition
 	Pairs     []*KeyValueExpression
@@ -420,10 +486,18 @@ type MapTypeExpression struct {
 	RBracketPos token.TokenPosition
 }
 
+type TupleTypeExpression struct {
+	LParenPos token.TokenPosition
+	Elements  []TypeExpression
+	RParenPos token.TokenPosition
+}
+
 type FunctionTypeExpression struct {
+	KeyWPos    token.TokenPosition
 	Identifier *IdentifierExpression
 	Arguments  []TypeExpression
-	ReturnType TypeExpression
+	RParenPos  token.TokenPosition
+	ReturnType TypeExpression // nil when the function returns void
 	// Generic Params
 	Params *GenericParametersClause
 }
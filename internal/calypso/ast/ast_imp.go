@@ -29,6 +29,13 @@ func (e *StringLiteral) Range() token.SyntaxRange {
 	}
 }
 
+func (e *InterpolatedStringLiteral) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.Start,
+		End:   e.End,
+	}
+}
+
 func (e *CharLiteral) Range() token.SyntaxRange {
 	return token.SyntaxRange{
 		Start: e.Pos,
@@ -70,6 +77,13 @@ func (e *ArrayLiteral) Range() token.SyntaxRange {
 	}
 }
 
+func (e *TupleLiteral) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.LParenPos,
+		End:   e.RParenPos,
+	}
+}
+
 func (e *MapLiteral) Range() token.SyntaxRange {
 	return token.SyntaxRange{
 		Start: e.LBracePos,
@@ -101,7 +115,21 @@ func (e *GroupedExpression) Range() token.SyntaxRange {
 	}
 }
 
+func (e *IfExpression) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.KeyWPos,
+		End:   e.RBracePos,
+	}
+}
+
 func (e *CallExpression) Range() token.SyntaxRange {
+	if e.IsPiped() {
+		return token.SyntaxRange{
+			Start: e.Arguments[0].Range().Start,
+			End:   e.RParenPos,
+		}
+	}
+
 	return token.SyntaxRange{
 		Start: e.Target.Range().Start,
 		End:   e.RParenPos,
@@ -240,6 +268,20 @@ func (e *WhileStatement) Range() token.SyntaxRange {
 	}
 }
 
+func (e *ForStatement) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.KeyWPos,
+		End:   e.Action.Range().End,
+	}
+}
+
+func (e *ForInStatement) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.KeyWPos,
+		End:   e.Action.Range().End,
+	}
+}
+
 func (e *ReturnStatement) Range() token.SyntaxRange {
 	return token.SyntaxRange{
 		Start: e.KeyWPos,
@@ -279,15 +321,45 @@ func (e *SwitchStatement) Range() token.SyntaxRange {
 }
 
 func (e *BreakStatement) Range() token.SyntaxRange {
+	if e.Label != nil {
+		return token.SyntaxRange{
+			Start: e.KeyWPos,
+			End:   e.Label.Range().End,
+		}
+	}
+
+	return token.SyntaxRange{
+		Start: e.KeyWPos,
+		End:   e.KeyWPos,
+	}
+}
+
+func (e *ContinueStatement) Range() token.SyntaxRange {
+	if e.Label != nil {
+		return token.SyntaxRange{
+			Start: e.KeyWPos,
+			End:   e.Label.Range().End,
+		}
+	}
+
 	return token.SyntaxRange{
 		Start: e.KeyWPos,
 		End:   e.KeyWPos,
 	}
 }
 
+func (e *LabeledStatement) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.Label.Range().Start,
+		End:   e.Stmt.Range().End,
+	}
+}
+
 func (s *IfStatement) statementNode()                    {}
 func (s *ExpressionStatement) statementNode()            {}
 func (s *WhileStatement) statementNode()                 {}
+func (s *ForStatement) statementNode()                   {}
+func (s *ForInStatement) statementNode()                 {}
 func (s *ReturnStatement) statementNode()                {}
 func (s *BlockStatement) statementNode()                 {}
 func (s *VariableStatement) statementNode()              {}
@@ -296,6 +368,8 @@ func (s *StructStatement) statementNode()                {}
 func (s *EnumStatement) statementNode()                  {}
 func (s *SwitchStatement) statementNode()                {}
 func (s *BreakStatement) statementNode()                 {}
+func (s *ContinueStatement) statementNode()              {}
+func (s *LabeledStatement) statementNode()               {}
 func (d *TypeStatement) statementNode()                  {}
 func (d *DereferenceAssignmentStatement) statementNode() {}
 
@@ -415,6 +489,25 @@ func (e *PointerTypeExpression) Range() token.SyntaxRange {
 	}
 }
 
+func (e *TupleTypeExpression) Range() token.SyntaxRange {
+	return token.SyntaxRange{
+		Start: e.LParenPos,
+		End:   e.RParenPos,
+	}
+}
+
+func (e *FunctionTypeExpression) Range() token.SyntaxRange {
+	end := e.RParenPos
+	if e.ReturnType != nil {
+		end = e.ReturnType.Range().End
+	}
+
+	return token.SyntaxRange{
+		Start: e.KeyWPos,
+		End:   end,
+	}
+}
+
 func IsTypeNode(n Node) bool {
 	switch n.(type) {
 	case *IdentifierExpression,
@@ -422,6 +515,8 @@ func IsTypeNode(n Node) bool {
 		*ArrayTypeExpression,
 		*MapTypeExpression,
 		*PointerTypeExpression,
+		*FunctionTypeExpression,
+		*TupleTypeExpression,
 		*FieldAccessExpression:
 		return true
 	}
@@ -429,6 +524,11 @@ func IsTypeNode(n Node) bool {
 	return false
 }
 
+// whether the call was written with the pipe operator, `x |> f(y)`
+func (e *CallExpression) IsPiped() bool {
+	return e.PipePos.Line != 0
+}
+
 func (e *CallArgument) GetLabel() string {
 	if e.Label != nil {
 		return e.Label.Value
@@ -438,13 +538,25 @@ func (e *CallArgument) GetLabel() string {
 }
 
 func (n *IntegerLiteral) String() string {
-	return fmt.Sprintf("%d", n.Value)
+	return fmt.Sprintf("%d%s", n.Value, n.Suffix)
 }
 func (n *FloatLiteral) String() string {
-	return fmt.Sprintf("%f", n.Value)
+	return fmt.Sprintf("%f%s", n.Value, n.Suffix)
 }
 func (n *StringLiteral) String() string {
-	return fmt.Sprintf("\"%s\"", n.Value)
+	return fmt.Sprintf("%q", n.Value)
+}
+func (n *InterpolatedStringLiteral) String() string {
+	str := "\""
+	for _, part := range n.Parts {
+		if s, ok := part.(*StringLiteral); ok {
+			q := s.String()
+			str += q[1 : len(q)-1]
+		} else {
+			str += "\\(" + part.String() + ")"
+		}
+	}
+	return str + "\""
 }
 func (n *CharLiteral) String() string {
 	return fmt.Sprintf("'%c'", n.Value)
@@ -468,6 +580,9 @@ func (n *CompositeLiteral) String() string {
 func (n *ArrayLiteral) String() string {
 	return ""
 }
+func (n *TupleLiteral) String() string {
+	return ""
+}
 func (n *MapLiteral) String() string {
 	return ""
 }
@@ -480,6 +595,9 @@ func (n *FunctionExpression) String() string {
 func (n *GroupedExpression) String() string {
 	return ""
 }
+func (n *IfExpression) String() string {
+	return ""
+}
 func (n *CallExpression) String() string {
 	return ""
 }
@@ -538,6 +656,12 @@ func (n *ExpressionStatement) String() string {
 func (n *WhileStatement) String() string {
 	return ""
 }
+func (n *ForStatement) String() string {
+	return ""
+}
+func (n *ForInStatement) String() string {
+	return ""
+}
 func (n *ReturnStatement) String() string {
 	return ""
 }
@@ -559,6 +683,12 @@ func (n *SwitchStatement) String() string {
 func (n *BreakStatement) String() string {
 	return ""
 }
+func (n *ContinueStatement) String() string {
+	return ""
+}
+func (n *LabeledStatement) String() string {
+	return ""
+}
 func (n *ConstantDeclaration) String() string {
 	return ""
 }
@@ -607,6 +737,12 @@ func (n *GenericArgumentsClause) String() string {
 func (n *PointerTypeExpression) String() string {
 	return ""
 }
+func (n *TupleTypeExpression) String() string {
+	return ""
+}
+func (n *FunctionTypeExpression) String() string {
+	return ""
+}
 
 func (n *CompositeLiteralBody) String() string {
 	return ""
@@ -0,0 +1,164 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/token"
+)
+
+// Returns a type or note to render alongside a node, an empty string is ignored
+type Annotator func(Node) string
+
+var positionType = reflect.TypeOf(token.TokenPosition{})
+
+/*
+Writes an indented tree of a node & its children
+
+	*ast.FunctionDeclaration {
+		Func: *ast.FunctionExpression {
+			Identifier: *ast.IdentifierExpression {
+				Value: "main"
+			}
+		}
+	}
+
+Source positions, nil, empty & false fields are omitted so dumps can be compared across builds.
+*/
+func Dump(w io.Writer, n any, annotate Annotator) error {
+	d := &dumper{
+		w:        w,
+		annotate: annotate,
+		visited:  make(map[uintptr]bool),
+	}
+
+	d.value(reflect.ValueOf(n), 0)
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Dumps the declarations of each file in a module
+func DumpModule(w io.Writer, m *Module, annotate Annotator) error {
+	for _, f := range m.Set.Files {
+		fmt.Fprintf(w, "// %s (module %s)\n", f.LexerFile.Path, f.ModuleName)
+		err := Dump(w, f.Nodes, annotate)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type dumper struct {
+	w        io.Writer
+	annotate Annotator
+	visited  map[uintptr]bool
+}
+
+func (d *dumper) printf(format string, args ...any) {
+	fmt.Fprintf(d.w, format, args...)
+}
+
+func (d *dumper) indent(depth int) {
+	io.WriteString(d.w, strings.Repeat("\t", depth))
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map:
+		return v.IsNil()
+	case reflect.Slice:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Uint8:
+		// default visibility
+		return v.Type() == reflect.TypeOf(PRIVATE) && v.Uint() == 0
+	}
+
+	return false
+}
+
+func (d *dumper) value(v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Interface:
+		d.value(v.Elem(), depth)
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.printf("nil")
+			return
+		}
+
+		if d.visited[v.Pointer()] {
+			d.printf("%s (cycle)", v.Type())
+			return
+		}
+
+		d.visited[v.Pointer()] = true
+		defer delete(d.visited, v.Pointer())
+
+		d.printf("%s", v.Type())
+
+		if node, ok := v.Interface().(Node); ok && d.annotate != nil {
+			if note := d.annotate(node); note != "" {
+				d.printf(" <%s>", note)
+			}
+		}
+
+		d.printf(" ")
+		d.fields(v.Elem(), depth)
+	case reflect.Struct:
+		d.fields(v, depth)
+	case reflect.Slice:
+		d.printf("[\n")
+		for i := 0; i < v.Len(); i++ {
+			d.indent(depth + 1)
+			d.value(v.Index(i), depth+1)
+			d.printf("\n")
+		}
+		d.indent(depth)
+		d.printf("]")
+	case reflect.String:
+		d.printf("%q", v.String())
+	case reflect.Uint8:
+		// tokens are the only byte valued fields
+		if v.Type() == reflect.TypeOf(token.ILLEGAL) {
+			d.printf("%s", v.Interface())
+			return
+		}
+		d.printf("%v", v.Interface())
+	default:
+		if v.IsValid() && v.CanInterface() {
+			d.printf("%v", v.Interface())
+		}
+	}
+}
+
+func (d *dumper) fields(v reflect.Value, depth int) {
+	if v.Kind() != reflect.Struct {
+		d.value(v, depth)
+		return
+	}
+
+	d.printf("{\n")
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		if !f.IsExported() || f.Type == positionType || isEmpty(fv) {
+			continue
+		}
+
+		d.indent(depth + 1)
+		d.printf("%s: ", f.Name)
+		d.value(fv, depth+1)
+		d.printf("\n")
+	}
+	d.indent(depth)
+	d.printf("}")
+}
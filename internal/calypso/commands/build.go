@@ -1,99 +1,70 @@
 package commands
 
 import (
+	"flag"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/mantton/calypso/internal/calypso/compile"
-	"github.com/mantton/calypso/internal/calypso/fs"
+	"github.com/mantton/calypso/internal/calypso/lir"
 )
 
-func build(paths []string) error {
+func build(args []string) error {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	opts := buildFlags(flags)
+	v := verbosityFlags(flags)
 
-	switch len(paths) {
-	case 0:
-		dir, err := os.Getwd()
+	usage := fmt.Sprintf("stage to stop at & artifact to write, one of %s", strings.Join(compile.EmitModes(), ", "))
+	flags.Func("emit", usage, func(s string) error {
+		emit, err := compile.ParseEmit(s)
+		opts.Emit = emit
+		return err
+	})
 
-		if err != nil {
-			return err
-		}
+	err := flags.Parse(args)
 
-		return buildFromDirectory(dir)
-	case 1:
-		return buildFromPath(paths[0])
-	default:
-		return buildFromFileList(paths)
+	if err != nil {
+		return err
 	}
+
+	opts.Log = v.logger()
+	_, err = buildTarget(flags.Args(), *opts)
+	return err
 }
 
-func buildFromPath(path string) error {
-	// Is File or Directory
+func buildFlags(flags *flag.FlagSet) *compile.Options {
+	opts := &compile.Options{}
+	flags.StringVar(&opts.Output, "o", "", "path of the produced artifact, \"-\" writes it to stdout")
+	flags.StringVar(&opts.BuildDir, "build-dir", "", "directory of intermediate build artifacts (default <package>/.calypso/build/<package name>)")
+	return opts
+}
 
-	f, err := os.Stat(path)
+// compiles the target, returning the path of the produced artifact
+func buildTarget(paths []string, opts compile.Options) (string, error) {
+	t, err := collectTarget(paths)
 
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return "", err
 	}
 
-	if f.IsDir() {
-		return buildFromDirectory(path)
+	if t.set != nil {
+		return compile.CompileFileSet(t.set, opts)
 	}
 
-	return buildFromFile(path)
+	return compile.CompilePackage(t.dir, opts)
 }
 
-func buildFromFile(pth string) error {
-	// set := &fs.FileSet{FilesPaths: []string{pth}}
-	panic("unimplemented")
-}
-
-/*
-Builds a list of provided files
-
-RULES:
-- All Files must be in the same directory
-- All Files must belong to the same module
-*/
-func buildFromFileList(paths []string) error {
-	set := &fs.FileSet{}
-
-	// Satisfy Rule 1
+// generates the lir of the target, without compiling it
+func generateTarget(paths []string, opts compile.Options) (*lir.Executable, error) {
+	t, err := collectTarget(paths)
 
-	// ensure all paths are files & collect directories of files
-	dirs := make(map[string]struct{})
-	for _, path := range paths {
-		file, err := os.Stat(path)
-
-		if err != nil {
-			return err
-		}
-
-		if file.IsDir() {
-			return fmt.Errorf("\"%s\" is a directory", file.Name())
-		}
-
-		dirs[filepath.Dir(path)] = struct{}{}
-
-		set.Paths = append(set.Paths, path)
+	if err != nil {
+		return nil, err
 	}
 
-	// map acts as a set in this case where we check that the dir lenght is just one, meaning one directory
-	if len(dirs) > 1 {
-		names := []string{}
-		for dir := range dirs {
-			names = append(names, dir)
-		}
-		s := strings.Join(names, ", ")
-		return fmt.Errorf("all files must be in the same directory, got: %s", s)
+	if t.set != nil {
+		return compile.GenerateFileSet(t.set, opts)
 	}
 
-	panic("unimplemented")
-	// return builder.CompileFileSet(set, typechecker.USER)
-}
-
-func buildFromDirectory(path string) error {
-	return compile.CompilePackage(path)
+	return compile.GeneratePackage(t.dir, opts)
 }
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"flag"
+
+	"github.com/mantton/calypso/internal/calypso/compile"
+)
+
+// Parses, resolves & typechecks the target without generating any code
+func check(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	v := verbosityFlags(flags)
+
+	err := flags.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	t, err := collectTarget(flags.Args())
+
+	if err != nil {
+		return err
+	}
+
+	opts := compile.Options{Log: v.logger()}
+
+	if t.set != nil {
+		return compile.CheckFileSet(t.set, opts)
+	}
+
+	return compile.CheckPackage(t.dir, opts)
+}
@@ -25,6 +25,20 @@ func Action(args []string) {
 			fmt.Println(err)
 			exitCode = 1
 		}
+	case "check":
+		err := check(arguments)
+		if err != nil {
+			fmt.Println(err)
+			exitCode = 1
+		}
+	case "run":
+		code, err := run(arguments)
+		if err != nil {
+			fmt.Println(err)
+			exitCode = 1
+		} else {
+			exitCode = code
+		}
 	default:
 		fmt.Println(usage())
 	}
@@ -37,9 +51,13 @@ Usage:
 		calypso [COMMAND] ARGUMENTS
 
 Commands:
-		build
+		build [-v|-vv] [-trace COMPONENTS] [-o OUTPUT] [-build-dir DIR] [-emit tokens|ast|typed-ast|lir|llvm-ir|bc|asm|obj|exe] [PATHS]
+		check [-v|-vv] [-trace COMPONENTS] [PATHS]
+		run [-v|-vv] [-trace COMPONENTS] [-interp] [-o OUTPUT] [-build-dir DIR] [PATHS] [-- ARGUMENTS]
 		help
 
+Note: Arguments following "--" are passed to the program when using "calypso run"
+Note: -trace accepts a comma separated list of compiler components, e.g. "typechecker,lirgen"
 Note: Use "calypso help [COMMAND] for more information about a specific command"
 `
 }
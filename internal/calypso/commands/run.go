@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/interp"
+)
+
+// Builds the target & executes the produced binary, returning the exit code of the program
+//
+// With -interp the generated lir is executed by the interpreter instead, which requires neither clang nor llvm-link
+func run(args []string) (int, error) {
+	args, programArgs := splitProgramArguments(args)
+
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	opts := buildFlags(flags)
+	v := verbosityFlags(flags)
+	interpret := flags.Bool("interp", false, "execute the program with the lir interpreter instead of compiling it")
+
+	err := flags.Parse(args)
+
+	if err != nil {
+		return 1, err
+	}
+
+	opts.Log = v.logger()
+
+	if *interpret {
+		program, err := generateTarget(flags.Args(), *opts)
+
+		if err != nil {
+			return 1, err
+		}
+
+		return interp.Run(program, interp.Options{
+			Args:   append([]string{programName(flags.Args())}, programArgs...),
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+		})
+	}
+
+	exe, err := buildTarget(flags.Args(), *opts)
+
+	if err != nil {
+		return 1, err
+	}
+
+	// a bare file name would be looked up in $PATH
+	exe, err = filepath.Abs(exe)
+
+	if err != nil {
+		return 1, err
+	}
+
+	cmd := exec.Command(exe, programArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// splits `calypso run [paths] -- [program arguments]`
+func splitProgramArguments(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+
+	return args, nil
+}
+
+// the name the interpreted program is run under, as the executable would be named after the file or package directory
+func programName(paths []string) string {
+	path := "."
+	if len(paths) != 0 {
+		path = paths[0]
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "main"
+	}
+
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
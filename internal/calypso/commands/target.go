@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/fs"
+)
+
+// The input of a command, either a package directory or a loose set of files
+type target struct {
+	dir string
+	set *fs.FileSet
+}
+
+func collectTarget(paths []string) (*target, error) {
+
+	switch len(paths) {
+	case 0:
+		dir, err := os.Getwd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &target{dir: dir}, nil
+	case 1:
+		return collectFromPath(paths[0])
+	default:
+		return collectFromFileList(paths)
+	}
+}
+
+func collectFromPath(path string) (*target, error) {
+	// Is File or Directory
+
+	f, err := os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.IsDir() {
+		return &target{dir: path}, nil
+	}
+
+	return &target{set: &fs.FileSet{Paths: []string{path}}}, nil
+}
+
+/*
+Collects a list of provided files
+
+RULES:
+- All Files must be in the same directory
+- All Files must belong to the same module
+*/
+func collectFromFileList(paths []string) (*target, error) {
+	set := &fs.FileSet{}
+
+	// Satisfy Rule 1
+
+	// ensure all paths are files & collect directories of files
+	dirs := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	for _, path := range paths {
+		file, err := os.Stat(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if file.IsDir() {
+			return nil, fmt.Errorf("\"%s\" is a directory", file.Name())
+		}
+
+		abs, err := filepath.Abs(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the same file provided twice would redeclare all its symbols
+		if _, ok := seen[abs]; ok {
+			continue
+		}
+
+		seen[abs] = struct{}{}
+		dirs[filepath.Dir(abs)] = struct{}{}
+
+		set.Paths = append(set.Paths, path)
+	}
+
+	// map acts as a set in this case where we check that the dir lenght is just one, meaning one directory
+	if len(dirs) > 1 {
+		names := []string{}
+		for dir := range dirs {
+			names = append(names, dir)
+		}
+		s := strings.Join(names, ", ")
+		return nil, fmt.Errorf("all files must be in the same directory, got: %s", s)
+	}
+
+	// Rule 2 is enforced by the parser when the set is collected into a single module
+	return &target{set: set}, nil
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/logging"
+)
+
+type verbosity struct {
+	info  bool
+	debug bool
+	trace string
+}
+
+func verbosityFlags(flags *flag.FlagSet) *verbosity {
+	v := &verbosity{}
+	flags.BoolVar(&v.info, "v", false, "log the stages of the pipeline")
+	flags.BoolVar(&v.debug, "vv", false, "log the stages of the pipeline & trace every node visited")
+	flags.StringVar(&v.trace, "trace", "", "comma separated components to trace regardless of verbosity, e.g. typechecker,lirgen")
+	return v
+}
+
+// trace output is written to stderr so it never mixes with artifacts written to stdout
+func (v *verbosity) logger() *logging.Logger {
+	level := logging.Silent
+
+	if v.debug {
+		level = logging.Debug
+	} else if v.info {
+		level = logging.Info
+	}
+
+	return logging.New(os.Stderr, level, strings.Split(v.trace, ","))
+}
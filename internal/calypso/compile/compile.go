@@ -1,42 +1,209 @@
 package compile
 
 import (
-	"fmt"
+	"errors"
+	"path/filepath"
 
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/fs"
+	"github.com/mantton/calypso/internal/calypso/lir"
 	"github.com/mantton/calypso/internal/calypso/lirgen"
-	"github.com/mantton/calypso/internal/calypso/llir"
+	"github.com/mantton/calypso/internal/calypso/logging"
 	"github.com/mantton/calypso/internal/calypso/resolver"
 	"github.com/mantton/calypso/internal/calypso/typechecker"
+	"github.com/mantton/calypso/internal/calypso/types"
 )
 
-const DEBUG = false
+type Options struct {
+	Output   string          // path of the final artifact, defaults to `<BuildDir>/<package name>`
+	BuildDir string          // directory of intermediate artifacts, defaults to `<package>/.calypso/build/<package name>`
+	Emit     Emit            // stage the pipeline stops at, defaults to an executable
+	Log      *logging.Logger // trace output, nil is silent
+}
 
-func CompilePackage(path string) error {
-	// Resolve AST & Imports
-	fmt.Println("\n\nAST GEN")
-	packages, err := resolver.ParseAndResolve(path)
+// Compiles a package, returning the path of the produced artifact
+func CompilePackage(path string, opts Options) (string, error) {
+	packages, err := resolvePackage(path, opts.Log)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	fmt.Println("\n\nTypeCheck")
-	typedPackages, err := typechecker.CheckPackages(packages)
+	return compilePackages(packages, opts)
+}
+
+// Compiles a set of files which are not part of a package directory, returning the path of the produced artifact
+func CompileFileSet(set *fs.FileSet, opts Options) (string, error) {
+	packages, err := resolveFileSet(set, opts.Log)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	fmt.Println("\n\nLIR GEN")
-	exec, err := lirgen.Generate(packages, typedPackages)
+	return compilePackages(packages, opts)
+}
 
+// Parses, resolves & typechecks a package, stopping before any code is generated
+func CheckPackage(path string, opts Options) error {
+	packages, err := resolvePackage(path, opts.Log)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("\n\nLLVM-IR GEN")
-	err = llir.Compile(exec)
+	_, err = typechecker.CheckPackages(packages, opts.Log)
+	return err
+}
 
+// Parses, resolves & typechecks a set of files, stopping before any code is generated
+func CheckFileSet(set *fs.FileSet, opts Options) error {
+	packages, err := resolveFileSet(set, opts.Log)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	_, err = typechecker.CheckPackages(packages, opts.Log)
+	return err
+}
+
+// Generates the LIR of a package, stopping before it is compiled
+func GeneratePackage(path string, opts Options) (*lir.Executable, error) {
+	packages, err := resolvePackage(path, opts.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkAndGenerate(packages, opts.Log)
+}
+
+// Generates the LIR of a set of files, stopping before it is compiled
+func GenerateFileSet(set *fs.FileSet, opts Options) (*lir.Executable, error) {
+	packages, err := resolveFileSet(set, opts.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkAndGenerate(packages, opts.Log)
+}
+
+func resolvePackage(path string, log *logging.Logger) ([]*ast.Package, error) {
+	// Resolve AST & Imports
+	log.For("compile").Infof("resolving %s", path)
+	return resolver.ParseAndResolve(path)
+}
+
+func resolveFileSet(set *fs.FileSet, log *logging.Logger) ([]*ast.Package, error) {
+	pkg, err := fs.CreateFilePackage(set)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve AST & Imports
+	log.For("compile").Infof("resolving %d file(s) as %s", len(set.Paths), pkg.Config.Package.Name)
+	return resolver.ParseAndResolvePackage(pkg)
+}
+
+func compilePackages(packages []*ast.Package, opts Options) (string, error) {
+	pkg, err := target(packages)
+	if err != nil {
+		return "", err
+	}
+
+	opts = opts.withDefaults(pkg)
+	log := opts.Log.For("compile")
+
+	switch opts.Emit {
+	case EmitTokens:
+		return opts.Output, writeArtifact(opts.Output, dumpTokens(pkg))
+	case EmitAST:
+		dump, err := dumpAST(pkg, nil)
+		if err != nil {
+			return "", err
+		}
+		return opts.Output, writeArtifact(opts.Output, dump)
+	}
+
+	log.Infof("typechecking %d package(s)", len(packages))
+	typedPackages, err := typechecker.CheckPackages(packages, opts.Log)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Emit == EmitTypedAST {
+		dump, err := dumpAST(pkg, typedPackages)
+		if err != nil {
+			return "", err
+		}
+		return opts.Output, writeArtifact(opts.Output, dump)
+	}
+
+	exec, err := generate(packages, typedPackages, opts.Log)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Emit == EmitLIR {
+		return opts.Output, writeArtifact(opts.Output, dumpLIR(exec))
+	}
+
+	log.Infof("compiling %s to %s", opts.Emit, opts.Output)
+	err = compileExecutable(exec, opts)
+
+	if err != nil {
+		return "", err
+	}
+	return opts.Output, nil
+}
+
+func checkAndGenerate(packages []*ast.Package, log *logging.Logger) (*lir.Executable, error) {
+	log.For("compile").Infof("typechecking %d package(s)", len(packages))
+	typedPackages, err := typechecker.CheckPackages(packages, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return generate(packages, typedPackages, log)
+}
+
+func generate(packages []*ast.Package, typedPackages *types.PackageMap, log *logging.Logger) (*lir.Executable, error) {
+	log.For("compile").Infof("generating lir")
+	exec, err := lirgen.Generate(packages, typedPackages, log)
+	if err != nil {
+		return nil, err
+	}
+
+	if DEBUG {
+		log.For("compile").Infof("validating lir")
+		err = lir.Validate(exec)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exec, nil
+}
+
+func (o Options) withDefaults(pkg *ast.Package) Options {
+	if o.Emit == "" {
+		o.Emit = EmitExecutable
+	}
+
+	// files built without a package directory share their directory, keyed by the name of the package each one synthesizes
+	if o.BuildDir == "" {
+		o.BuildDir = filepath.Join(pkg.Info.Path, ".calypso", "build", pkg.Name())
+	}
+
+	if o.Output == "" {
+		o.Output = filepath.Join(o.BuildDir, pkg.Name()+emitExtensions[o.Emit])
+	}
+
+	return o
+}
+
+func target(packages []*ast.Package) (*ast.Package, error) {
+	for _, pkg := range packages {
+		if pkg.IsTarget {
+			return pkg, nil
+		}
+	}
+
+	return nil, errors.New("no target package was resolved")
 }
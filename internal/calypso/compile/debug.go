@@ -0,0 +1,6 @@
+//go:build debug
+
+package compile
+
+// Debug builds, `go build -tags=debug`, validate intermediate representations between stages
+const DEBUG = true
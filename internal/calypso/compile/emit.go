@@ -0,0 +1,135 @@
+package compile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+// The stage at which the pipeline stops & the artifact written
+type Emit string
+
+const (
+	EmitTokens     Emit = "tokens"
+	EmitAST        Emit = "ast"
+	EmitTypedAST   Emit = "typed-ast"
+	EmitLIR        Emit = "lir"
+	EmitLLVMIR     Emit = "llvm-ir"
+	EmitBitcode    Emit = "bc"
+	EmitAssembly   Emit = "asm"
+	EmitObject     Emit = "obj"
+	EmitExecutable Emit = "exe"
+)
+
+var emitExtensions = map[Emit]string{
+	EmitTokens:     ".tokens",
+	EmitAST:        ".ast",
+	EmitTypedAST:   ".ast",
+	EmitLIR:        ".lir",
+	EmitLLVMIR:     ".ll",
+	EmitBitcode:    ".bc",
+	EmitAssembly:   ".s",
+	EmitObject:     ".o",
+	EmitExecutable: "",
+}
+
+func ParseEmit(s string) (Emit, error) {
+	e := Emit(s)
+	if _, ok := emitExtensions[e]; !ok {
+		return "", fmt.Errorf("unknown emit mode \"%s\", expected one of %s", s, strings.Join(EmitModes(), ", "))
+	}
+
+	return e, nil
+}
+
+func EmitModes() []string {
+	return []string{
+		string(EmitTokens),
+		string(EmitAST),
+		string(EmitTypedAST),
+		string(EmitLIR),
+		string(EmitLLVMIR),
+		string(EmitBitcode),
+		string(EmitAssembly),
+		string(EmitObject),
+		string(EmitExecutable),
+	}
+}
+
+// writes an artifact, creating the directory it is written to, "-" writes to stdout
+func writeArtifact(path string, content []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
+
+// modules of a package, sorted by name
+func sortedModules(pkg *ast.Package) []*ast.Module {
+	modules := []*ast.Module{}
+	for _, m := range pkg.Modules {
+		modules = append(modules, m)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name() < modules[j].Name()
+	})
+	return modules
+}
+
+func dumpTokens(pkg *ast.Package) []byte {
+	var b bytes.Buffer
+	for _, m := range sortedModules(pkg) {
+		for _, f := range m.Set.Files {
+			fmt.Fprintf(&b, "// %s\n", f.LexerFile.Path)
+			for _, t := range f.LexerFile.Tokens {
+				fmt.Fprintf(&b, "%d:%d\t%-12s %q\n", t.Pos.Line, t.Pos.Offset, t.Tok, t.Lit)
+			}
+		}
+	}
+
+	return b.Bytes()
+}
+
+func dumpAST(pkg *ast.Package, tmap *types.PackageMap) ([]byte, error) {
+	var b bytes.Buffer
+	for _, m := range sortedModules(pkg) {
+		var annotate ast.Annotator
+
+		// annotate nodes with their resolved types
+		if tmap != nil {
+			table := tmap.Modules[m.ID()].Table
+			annotate = func(n ast.Node) string {
+				if t := table.GetNodeType(n); t != nil {
+					return t.String()
+				}
+				return ""
+			}
+		}
+
+		err := ast.DumpModule(&b, m, annotate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+func dumpLIR(exec *lir.Executable) []byte {
+	return []byte(lir.PrintExecutable(exec))
+}
@@ -0,0 +1,62 @@
+package compile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/fs"
+	"github.com/mantton/calypso/internal/calypso/parser"
+)
+
+// parses a single file into the only module of a target package
+func filePackage(t *testing.T, input string) *ast.Package {
+	t.Helper()
+	file, errs := parser.ParseString(input)
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	cfg := &fs.Config{}
+	cfg.Package.Name = file.ModuleName
+	pkg := ast.NewPackage(fs.NewPackage("", cfg))
+	pkg.IsTarget = true
+
+	m := ast.NewModule(nil, pkg)
+	m.Set = &ast.FileSet{ModuleName: file.ModuleName, Files: []*ast.File{file}}
+	pkg.AddModule(m)
+
+	return pkg
+}
+
+func TestEmitCreatesBuildDir(t *testing.T) {
+	input := `
+		module main;
+
+		fn main() {
+			const a = 1;
+		}
+	`
+
+	for _, emit := range []Emit{EmitTokens, EmitAST, EmitTypedAST, EmitLIR} {
+		// the build directory does not exist yet
+		dir := filepath.Join(t.TempDir(), "build", "main")
+		path, err := compilePackages([]*ast.Package{filePackage(t, input)}, Options{BuildDir: dir, Emit: emit})
+
+		if err != nil {
+			t.Errorf("emit %s: %s", emit, err)
+			continue
+		}
+
+		if expected := filepath.Join(dir, "main"+emitExtensions[emit]); path != expected {
+			t.Errorf("emit %s: expected the artifact at %s, found %s", emit, expected, path)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil || len(content) == 0 {
+			t.Errorf("emit %s: expected the artifact to be written, %v", emit, err)
+		}
+	}
+}
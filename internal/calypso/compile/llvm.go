@@ -0,0 +1,26 @@
+//go:build !nollvm
+
+package compile
+
+import (
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/llir"
+)
+
+var emitArtifacts = map[Emit]llir.Artifact{
+	EmitLLVMIR:     llir.LLVMIR,
+	EmitBitcode:    llir.Bitcode,
+	EmitAssembly:   llir.Assembly,
+	EmitObject:     llir.Object,
+	EmitExecutable: llir.Executable,
+}
+
+// Compiles the lir of a program with llvm, producing the artifact requested by the options
+func compileExecutable(exec *lir.Executable, opts Options) error {
+	return llir.Compile(exec, llir.Options{
+		Output:   opts.Output,
+		BuildDir: opts.BuildDir,
+		Artifact: emitArtifacts[opts.Emit],
+		Write:    writeArtifact,
+	})
+}
@@ -0,0 +1,50 @@
+//go:build !nollvm
+
+package compile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+)
+
+// binary artifacts written to "-" go to stdout rather than a file of that name
+func TestEmitBinaryToStdout(t *testing.T) {
+	input := `
+		module main;
+
+		fn main() {}
+	`
+
+	dir := t.TempDir()
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	for _, emit := range []Emit{EmitBitcode, EmitObject} {
+		out, err := os.Create(filepath.Join(dir, string(emit)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		os.Stdout = out
+		_, err = compilePackages([]*ast.Package{filePackage(t, input)}, Options{BuildDir: filepath.Join(dir, "build"), Output: "-", Emit: emit})
+		os.Stdout = stdout
+		out.Close()
+
+		if err != nil {
+			t.Errorf("emit %s: %s", emit, err)
+			continue
+		}
+
+		if info, err := os.Stat(out.Name()); err != nil || info.Size() == 0 {
+			t.Errorf("emit %s: expected the artifact on stdout", emit)
+		}
+	}
+
+	if _, err := os.Stat("-"); err == nil {
+		os.Remove("-")
+		t.Errorf("expected no file named \"-\"")
+	}
+}
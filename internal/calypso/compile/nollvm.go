@@ -0,0 +1,14 @@
+//go:build nollvm
+
+package compile
+
+import (
+	"fmt"
+
+	"github.com/mantton/calypso/internal/calypso/lir"
+)
+
+// Builds without llvm, `go build -tags=nollvm`, stop at the lir & execute it with the interpreter
+func compileExecutable(exec *lir.Executable, opts Options) error {
+	return fmt.Errorf("cannot emit %s, calypso was built without llvm, emit lir or use run -interp", opts.Emit)
+}
@@ -0,0 +1,5 @@
+//go:build !debug
+
+package compile
+
+const DEBUG = false
@@ -0,0 +1,244 @@
+/*
+Lossless syntax trees
+
+The tree of a file retains every token along with the whitespace & comments preceding it, printing the tree reproduces the exact source
+of a file scanned with trivia. Nodes are built from the ast of the file, each node spans the tokens its ast node was parsed from.
+Tokens which belong to no ast node, such as the module header, are children of the innermost node spanning them.
+*/
+package cst
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/lexer"
+	"github.com/mantton/calypso/internal/calypso/token"
+)
+
+type Node struct {
+	AST      ast.Node // the ast node spanning the children, nil for the root of a file
+	Children []Child
+}
+
+// A child of a node, either a token or a nested node
+type Child struct {
+	Token *Token
+	Node  *Node
+}
+
+type Token struct {
+	token.ScannedToken
+	Text string // the source of the token, unlike its literal the quotes of strings are included
+}
+
+// Writes the source of the tree
+func (n *Node) Print(w io.Writer) error {
+	for _, c := range n.Children {
+		if c.Node != nil {
+			if err := c.Node.Print(w); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, t := range c.Token.Trivia {
+			if _, err := io.WriteString(w, t.Lit); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(w, c.Token.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) String() string {
+	b := &strings.Builder{}
+	n.Print(b)
+	return b.String()
+}
+
+// the tokens of the tree, in source order
+func (n *Node) Tokens() []*Token {
+	tokens := []*Token{}
+
+	for _, c := range n.Children {
+		if c.Node != nil {
+			tokens = append(tokens, c.Node.Tokens()...)
+		} else {
+			tokens = append(tokens, c.Token)
+		}
+	}
+
+	return tokens
+}
+
+// Builds the lossless tree of a parsed file
+func Build(f *ast.File) *Node {
+	b := &builder{
+		file:    f.LexerFile,
+		visited: make(map[uintptr]bool),
+	}
+
+	root := &span{
+		first:    0,
+		last:     len(f.LexerFile.Tokens) - 1,
+		children: b.collect(reflect.ValueOf(f.Nodes)),
+	}
+
+	return b.build(root)
+}
+
+// the tokens an ast node spans, by index
+type span struct {
+	node        ast.Node
+	first, last int
+	children    []*span
+}
+
+type builder struct {
+	file    *lexer.File
+	visited map[uintptr]bool
+}
+
+// collects the spans of the ast nodes within a value
+func (b *builder) collect(v reflect.Value) []*span {
+	switch v.Kind() {
+	case reflect.Interface:
+		return b.collect(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() || b.visited[v.Pointer()] {
+			return nil
+		}
+
+		b.visited[v.Pointer()] = true
+
+		if node, ok := v.Interface().(ast.Node); ok {
+			if s := b.span(node, b.collect(v.Elem())); s != nil {
+				return []*span{s}
+			}
+
+			return nil
+		}
+
+		return b.collect(v.Elem())
+	case reflect.Struct:
+		spans := []*span{}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				spans = append(spans, b.collect(v.Field(i))...)
+			}
+		}
+		return spans
+	case reflect.Slice, reflect.Array:
+		spans := []*span{}
+		for i := 0; i < v.Len(); i++ {
+			spans = append(spans, b.collect(v.Index(i))...)
+		}
+		return spans
+	case reflect.Map:
+		spans := []*span{}
+		iter := v.MapRange()
+		for iter.Next() {
+			spans = append(spans, b.collect(iter.Key())...)
+			spans = append(spans, b.collect(iter.Value())...)
+		}
+		return spans
+	}
+
+	return nil
+}
+
+// the span of a node, covering its range & the spans of its children
+func (b *builder) span(node ast.Node, children []*span) *span {
+	s := &span{node: node, first: -1, last: -1, children: children}
+
+	if r, ok := rangeOf(node); ok && r.Start.Line != 0 && r.End.Line != 0 {
+		tokens := b.file.Tokens
+
+		s.first = sort.Search(len(tokens), func(i int) bool {
+			return tokens[i].Pos.Start >= r.Start.Start
+		})
+
+		s.last = sort.Search(len(tokens), func(i int) bool {
+			return tokens[i].Pos.Start > r.End.Start
+		}) - 1
+
+		if s.first > s.last {
+			s.first, s.last = -1, -1
+		}
+	}
+
+	for _, c := range children {
+		if s.first == -1 || c.first < s.first {
+			s.first = c.first
+		}
+
+		if c.last > s.last {
+			s.last = c.last
+		}
+	}
+
+	if s.first == -1 {
+		return nil
+	}
+
+	return s
+}
+
+// the range of a node, nodes missing children may be unable to compute theirs
+func rangeOf(node ast.Node) (r token.SyntaxRange, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	return node.Range(), true
+}
+
+// builds the node of a span, tokens between the spans of its children are its own. children overlapping a sibling are flattened into tokens
+func (b *builder) build(s *span) *Node {
+	n := &Node{AST: s.node}
+
+	sort.SliceStable(s.children, func(i, j int) bool {
+		return s.children[i].first < s.children[j].first
+	})
+
+	cursor := s.first
+	for _, c := range s.children {
+		if c.first < cursor || c.last > s.last {
+			continue
+		}
+
+		for ; cursor < c.first; cursor++ {
+			n.Children = append(n.Children, b.token(cursor))
+		}
+
+		n.Children = append(n.Children, Child{Node: b.build(c)})
+		cursor = c.last + 1
+	}
+
+	for ; cursor <= s.last; cursor++ {
+		n.Children = append(n.Children, b.token(cursor))
+	}
+
+	return n
+}
+
+func (b *builder) token(idx int) Child {
+	tok := b.file.Tokens[idx]
+
+	return Child{
+		Token: &Token{
+			ScannedToken: tok,
+			Text:         string(b.file.Chars[tok.Pos.Start:tok.Pos.End]),
+		},
+	}
+}
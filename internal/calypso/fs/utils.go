@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -60,6 +61,41 @@ func CreatePackage(dir string) (*Package, error) {
 	return p, nil
 }
 
+// Synthesizes a package around a loose set of source files which do not live in a package directory.
+// The files make up the base module of the package, which is named after the file (or its directory when multiple files are provided)
+func CreateFilePackage(set *FileSet) (*Package, error) {
+	if set == nil || len(set.Paths) == 0 {
+		return nil, errors.New("no source files provided")
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(set.Paths[0]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	cfg.Package.Version = "0.0.0"
+
+	if len(set.Paths) == 1 {
+		base := filepath.Base(set.Paths[0])
+		cfg.Package.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	} else {
+		cfg.Package.Name = filepath.Base(dir)
+	}
+
+	p := NewPackage(dir, cfg)
+
+	mod := NewModule(dir, nil)
+	for _, path := range set.Paths {
+		mod.AddFile(path)
+	}
+
+	p.AddModule(mod)
+
+	return p, nil
+}
+
 func (p *Package) CollectModules() error {
 
 	// 2 - Read `src` folder of package
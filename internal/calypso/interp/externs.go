@@ -0,0 +1,164 @@
+package interp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/lir"
+)
+
+/*
+Calls an external function
+
+Only a small part of libc is provided, enough for programs that exit with a status, allocate memory, build strings, read stdin & write to stdout.
+*/
+func (i *interpreter) extern(fn *lir.Function, args []value) value {
+	switch fn.Name {
+	case "exit":
+		i.arity(fn, args, 1)
+		panic(exitSignal(args[0].int()))
+	case "abort":
+		throw("program aborted")
+	case "malloc":
+		i.arity(fn, args, 1)
+		return intValue(i.mem.malloc(args[0].uint()), 8)
+	case "calloc":
+		// the heap is zeroed as it grows
+		i.arity(fn, args, 2)
+		return intValue(i.mem.malloc(args[0].uint()*args[1].uint()), 8)
+	case "free":
+		i.arity(fn, args, 1)
+		return nil
+	case "getchar":
+		i.arity(fn, args, 0)
+		c, err := i.stdin.ReadByte()
+		if err != nil {
+			return i.result(fn, -1)
+		}
+		return i.result(fn, int64(c))
+	case "read":
+		// only stdin may be read
+		i.arity(fn, args, 3)
+		if args[0].int() != 0 {
+			throw("@read of file descriptor %d, only stdin is supported", args[0].int())
+		}
+
+		buf := make([]byte, args[2].uint())
+		n, err := i.stdin.Read(buf)
+		if err != nil && err != io.EOF {
+			return i.result(fn, -1)
+		}
+
+		i.mem.store(args[1].uint(), value(buf[:n]))
+		return i.result(fn, int64(n))
+	case "strlen":
+		i.arity(fn, args, 1)
+		return i.result(fn, int64(len(i.mem.cstring(args[0].uint()))))
+	case "strcpy":
+		i.arity(fn, args, 2)
+		i.mem.store(args[0].uint(), value(i.mem.cstring(args[1].uint())+"\x00"))
+		return args[0]
+	case "strcat":
+		i.arity(fn, args, 2)
+		dst := args[0].uint()
+		end := dst + uint64(len(i.mem.cstring(dst)))
+		i.mem.store(end, value(i.mem.cstring(args[1].uint())+"\x00"))
+		return args[0]
+	case "putchar":
+		i.arity(fn, args, 1)
+		fmt.Fprint(i.stdout, string(rune(args[0].int())))
+		return i.result(fn, args[0].int())
+	case "puts":
+		i.arity(fn, args, 1)
+		fmt.Fprintln(i.stdout, i.mem.cstring(args[0].uint()))
+		return i.result(fn, 0)
+	case "printf":
+		if len(args) == 0 {
+			throw("@printf requires a format")
+		}
+
+		out := i.printf(i.mem.cstring(args[0].uint()), args[1:])
+		fmt.Fprint(i.stdout, out)
+		return i.result(fn, int64(len(out)))
+	}
+
+	throw("external function @%s is not supported by the interpreter", fn.Name)
+	return nil
+}
+
+func (i *interpreter) arity(fn *lir.Function, args []value, n int) {
+	if len(args) != n {
+		throw("@%s called with %d argument(s), expected %d", fn.Name, len(args), n)
+	}
+}
+
+// sizes an integer result to the declared result of an extern
+func (i *interpreter) result(fn *lir.Function, n int64) value {
+	return intValue(uint64(n), i.layout.sizeOf(fn.Signature().Result.Type()))
+}
+
+/*
+Formats the arguments of a printf call
+
+Supports the d, i, u, x, X, o, c, s, f, e, g & p conversions along with flags, width & precision. Length modifiers are accepted & ignored, arguments are read at the width they were passed.
+*/
+func (i *interpreter) printf(format string, args []value) string {
+	out := &strings.Builder{}
+
+	next := func() value {
+		if len(args) == 0 {
+			throw("@printf has fewer arguments than its format requires")
+		}
+
+		v := args[0]
+		args = args[1:]
+		return v
+	}
+
+	for idx := 0; idx < len(format); idx++ {
+		if format[idx] != '%' {
+			out.WriteByte(format[idx])
+			continue
+		}
+
+		// flags, width & precision carry over to the go verb
+		spec := "%"
+		idx++
+		for idx < len(format) && strings.IndexByte("-+ #0123456789.", format[idx]) != -1 {
+			spec += string(format[idx])
+			idx++
+		}
+
+		for idx < len(format) && strings.IndexByte("hlLqjzt", format[idx]) != -1 {
+			idx++
+		}
+
+		if idx == len(format) {
+			throw("@printf format ends in an incomplete conversion")
+		}
+
+		switch verb := format[idx]; verb {
+		case '%':
+			out.WriteByte('%')
+		case 'd', 'i':
+			fmt.Fprintf(out, spec+"d", next().int())
+		case 'u':
+			fmt.Fprintf(out, spec+"d", next().uint())
+		case 'x', 'X', 'o':
+			fmt.Fprintf(out, spec+string(verb), next().uint())
+		case 'c':
+			fmt.Fprintf(out, spec+"c", rune(next().int()))
+		case 's':
+			fmt.Fprintf(out, spec+"s", i.mem.cstring(next().uint()))
+		case 'f', 'F', 'e', 'E', 'g', 'G':
+			fmt.Fprintf(out, spec+string(verb), next().float())
+		case 'p':
+			fmt.Fprintf(out, "%#x", next().uint())
+		default:
+			throw("@printf conversion %%%c is not supported", verb)
+		}
+	}
+
+	return out.String()
+}
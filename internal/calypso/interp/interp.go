@@ -0,0 +1,537 @@
+package interp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+// the deepest call stack a program may build before it is stopped
+const maxDepth = 10000
+
+type interpreter struct {
+	exec   *lir.Executable
+	mem    *memory
+	layout *layout
+	stdin  *bufio.Reader
+	stdout io.Writer
+	trace  []*lir.Function // functions being executed, innermost last
+
+	schedules map[*lir.Function]schedule
+	functions map[uint64]*lir.Function // functions used as values, keyed by the id standing in for their address
+	strings   map[string]uint64        // the addresses of string constants, laid out on the heap at their first use
+}
+
+// the instructions executed by each block of a function, including those materialized at their first use
+type schedule map[*lir.Block][]lir.Instruction
+
+// the registers of a function being executed
+type frame struct {
+	fn        *lir.Function
+	registers map[lir.Value]value
+	prev      *lir.Block // the block control arrived from, used to resolve phi nodes
+}
+
+// raised by the exit extern to unwind every frame
+type exitSignal int
+
+// The environment a program is executed in
+type Options struct {
+	Args   []string  // the arguments of the program, led by its name
+	Stdin  io.Reader // nil reads as an empty stream
+	Stdout io.Writer // nil discards the output
+}
+
+/*
+Executes a program without compiling it, returning its exit code
+
+The entry function synthesized by lirgen is called & its result is the exit code, unless the program calls exit.
+An entry declaring two parameters receives the arguments of the program as `argc` & `argv`, as in C.
+Programs may only call the externs the interpreter provides, see externs.go.
+*/
+func Run(e *lir.Executable, opts Options) (code int, err error) {
+	entry := findEntry(e)
+	if entry == nil {
+		return 0, fmt.Errorf("cannot find an entry point, expected a function named main")
+	}
+
+	if opts.Stdin == nil {
+		opts.Stdin = strings.NewReader("")
+	}
+
+	if opts.Stdout == nil {
+		opts.Stdout = io.Discard
+	}
+
+	i := &interpreter{
+		exec:   e,
+		mem:    &memory{},
+		layout: &layout{exec: e, sizes: make(map[types.Type]uint64)},
+		stdin:  bufio.NewReader(opts.Stdin),
+		stdout: opts.Stdout,
+
+		schedules: make(map[*lir.Function]schedule),
+		functions: make(map[uint64]*lir.Function),
+		strings:   make(map[string]uint64),
+	}
+
+	defer func() {
+		r := recover()
+		switch r := r.(type) {
+		case nil:
+		case exitSignal:
+			code = int(r)
+		case *RuntimeError:
+			for _, fn := range i.trace {
+				r.Trace = append(r.Trace, fn.Name)
+			}
+			err = r
+		default:
+			panic(r)
+		}
+	}()
+
+	var args []value
+	if len(entry.Parameters) == 2 {
+		args = i.arguments(entry, opts.Args)
+	}
+
+	result := i.call(entry, args)
+	return int(result.int()), nil
+}
+
+// lays out the arguments of the program on the heap, as `argc` & a null terminated `argv`
+func (i *interpreter) arguments(entry *lir.Function, args []string) []value {
+	argv := i.mem.malloc(uint64(len(args)+1) * 8)
+
+	for idx, arg := range args {
+		addr := i.mem.malloc(uint64(len(arg) + 1))
+		i.mem.store(addr, value(arg))
+		i.mem.store(argv+uint64(idx)*8, intValue(addr, 8))
+	}
+
+	argc := intValue(uint64(len(args)), i.layout.sizeOf(entry.Parameters[0].Symbol))
+	return []value{argc, intValue(argv, 8)}
+}
+
+// the value of a constant, strings are the address of their nul terminated bytes
+func (i *interpreter) constant(c *lir.Constant) value {
+	s, ok := c.Value.(string)
+	if !ok {
+		return i.layout.constant(c)
+	}
+
+	addr, ok := i.strings[s]
+	if !ok {
+		addr = i.mem.malloc(uint64(len(s) + 1))
+		i.mem.store(addr, value(s))
+		i.strings[s] = addr
+	}
+
+	return intValue(addr, 8)
+}
+
+func findEntry(e *lir.Executable) *lir.Function {
+	for _, m := range e.Modules {
+		if fn, ok := m.Functions["main"]; ok && !fn.External {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+func (i *interpreter) call(fn *lir.Function, args []value) value {
+	if fn.External {
+		return i.extern(fn, args)
+	}
+
+	if len(i.trace) == maxDepth {
+		throw("stack overflow, exceeded %d nested calls", maxDepth)
+	}
+
+	if len(fn.Blocks) == 0 {
+		throw("@%s has no body", fn.Name)
+	}
+
+	if len(args) != len(fn.Parameters) {
+		throw("@%s called with %d argument(s), expected %d", fn.Name, len(args), len(fn.Parameters))
+	}
+
+	i.trace = append(i.trace, fn)
+	height := len(i.mem.stack)
+
+	f := &frame{
+		fn:        fn,
+		registers: make(map[lir.Value]value),
+	}
+
+	for idx, p := range fn.Parameters {
+		f.registers[p] = args[idx]
+	}
+
+	result := i.run(f)
+
+	// composites are returned by address, so the frame is kept alive for the caller
+	if _, ok := i.exec.CompositeOf(fn.Signature().Result.Type()); !ok {
+		i.mem.release(height)
+	}
+
+	i.trace = i.trace[:len(i.trace)-1]
+	return result
+}
+
+// executes the blocks of a function until it returns
+func (i *interpreter) run(f *frame) value {
+	blk := f.fn.Blocks[0]
+
+	for {
+		next, result, done := i.block(f, blk)
+		if done {
+			return result
+		}
+
+		f.prev, blk = blk, next
+	}
+}
+
+// executes a block, returning either its successor or the result of the function
+func (i *interpreter) block(f *frame, blk *lir.Block) (*lir.Block, value, bool) {
+	instructions := i.schedule(f.fn)[blk]
+
+	// phi nodes at the head of a block are resolved together, against the values of the previous block
+	start := 0
+	incoming := []value{}
+	for _, instr := range instructions {
+		phi, ok := instr.(*lir.PHI)
+		if !ok {
+			break
+		}
+
+		incoming = append(incoming, i.phi(f, phi))
+		start++
+	}
+
+	for idx, v := range incoming {
+		f.registers[instructions[idx].(*lir.PHI)] = v
+	}
+
+	for _, instr := range instructions[start:] {
+		switch instr := instr.(type) {
+		case *lir.Return:
+			return nil, i.get(f, instr.Result), true
+		case *lir.ReturnVoid:
+			return nil, nil, true
+		case *lir.Branch:
+			return instr.Block, nil, false
+		case *lir.ConditionalBranch:
+			if i.get(f, instr.Condition).bool() {
+				return instr.Action, nil, false
+			}
+			return instr.Alternative, nil, false
+		case *lir.Switch:
+			v := i.get(f, instr.Value).uint()
+			for _, c := range instr.Blocks {
+				if i.get(f, c.Value).uint() == v {
+					return c.Block, nil, false
+				}
+			}
+			return instr.Done, nil, false
+		case *lir.Store:
+			v := i.get(f, instr.Value)
+			addr := i.get(f, instr.Address).uint()
+			i.mem.store(addr, v)
+		case lir.Value:
+			f.registers[instr] = i.eval(f, instr)
+		default:
+			throw("unsupported instruction %T", instr)
+		}
+	}
+
+	throw("b%d of @%s does not end in a terminator", blk.Index, f.fn.Name)
+	return nil, nil, false
+}
+
+/*
+Orders the instructions of a function as the llvm backend emits them
+
+Instructions referenced without being emitted in a block are materialized before their first use, in the block of that use.
+An instruction is only materialized once, later uses read the value it produced when its block last ran.
+Incoming values of phi nodes are read from the previous block rather than materialized.
+*/
+func (i *interpreter) schedule(fn *lir.Function) schedule {
+	if s, ok := i.schedules[fn]; ok {
+		return s
+	}
+
+	s := make(schedule)
+	placed := make(map[lir.Instruction]bool)
+
+	var place func(blk *lir.Block, instr lir.Instruction)
+	place = func(blk *lir.Block, instr lir.Instruction) {
+		v, isValue := instr.(lir.Value)
+		if isValue {
+			if placed[instr] || !lir.IsInstruction(v) {
+				return
+			}
+
+			placed[instr] = true
+		}
+
+		if _, ok := instr.(*lir.PHI); !ok {
+			for _, op := range lir.Operands(instr) {
+				place(blk, *op)
+			}
+		}
+
+		s[blk] = append(s[blk], instr)
+	}
+
+	for _, blk := range fn.Blocks {
+		for _, instr := range blk.Instructions {
+			place(blk, instr)
+		}
+	}
+
+	i.schedules[fn] = s
+	return s
+}
+
+// Returns the value of an operand, values that have not been produced yet are evaluated on demand
+func (i *interpreter) get(f *frame, v lir.Value) value {
+	switch v := v.(type) {
+	case *lir.Constant:
+		return i.constant(v)
+	case *lir.Global:
+		return i.constant(v.Value)
+	}
+
+	if r, ok := f.registers[v]; ok {
+		return r
+	}
+
+	r := i.eval(f, v)
+	f.registers[v] = r
+	return r
+}
+
+func (i *interpreter) eval(f *frame, v lir.Value) value {
+	switch v := v.(type) {
+	case *lir.Constant:
+		return i.constant(v)
+	case *lir.Global:
+		return i.constant(v.Value)
+	case *lir.Function:
+		id := uint64(v.ID())
+		i.functions[id] = v
+		return intValue(id, 8)
+
+	// Integer Arithmetic
+	case *lir.Add:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()+r.uint(), uint64(len(l)))
+	case *lir.Sub:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()-r.uint(), uint64(len(l)))
+	case *lir.Mul:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()*r.uint(), uint64(len(l)))
+	case *lir.UDiv:
+		l, r := i.get(f, v.Left), i.divisor(f, v.Right)
+		return intValue(l.uint()/r.uint(), uint64(len(l)))
+	case *lir.SDiv:
+		l, r := i.get(f, v.Left), i.divisor(f, v.Right)
+		return intValue(uint64(l.int()/r.int()), uint64(len(l)))
+	case *lir.URem:
+		l, r := i.get(f, v.Left), i.divisor(f, v.Right)
+		return intValue(l.uint()%r.uint(), uint64(len(l)))
+	case *lir.SRem:
+		l, r := i.get(f, v.Left), i.divisor(f, v.Right)
+		return intValue(uint64(l.int()%r.int()), uint64(len(l)))
+	case *lir.INeg:
+		r := i.get(f, v.Right)
+		return intValue(-r.uint(), uint64(len(r)))
+
+	// Float Arithmetic
+	case *lir.FAdd:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return floatValue(l.float()+r.float(), uint64(len(l)))
+	case *lir.FSub:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return floatValue(l.float()-r.float(), uint64(len(l)))
+	case *lir.FMul:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return floatValue(l.float()*r.float(), uint64(len(l)))
+	case *lir.FDiv:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return floatValue(l.float()/r.float(), uint64(len(l)))
+	case *lir.FRem:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return floatValue(math.Mod(l.float(), r.float()), uint64(len(l)))
+	case *lir.FNeg:
+		r := i.get(f, v.Right)
+		return floatValue(-r.float(), uint64(len(r)))
+
+	// Bitwise
+	case *lir.AND:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()&r.uint(), uint64(len(l)))
+	case *lir.OR:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()|r.uint(), uint64(len(l)))
+	case *lir.XOR:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()^r.uint(), uint64(len(l)))
+	case *lir.ShiftLeft:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()<<r.uint(), uint64(len(l)))
+	case *lir.LogicalShiftRight:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(l.uint()>>r.uint(), uint64(len(l)))
+	case *lir.ArithmeticShiftRight:
+		l, r := i.get(f, v.Left), i.get(f, v.Right)
+		return intValue(uint64(l.int()>>r.uint()), uint64(len(l)))
+
+	// Comparisons
+	case *lir.ICmp:
+		return boolValue(compareInt(v.Comparison, i.get(f, v.Left), i.get(f, v.Right)))
+	case *lir.FCmp:
+		return boolValue(compareFloat(v.Comparison, i.get(f, v.Left).float(), i.get(f, v.Right).float()))
+
+	// Memory
+	case *lir.Allocate:
+		size := i.layout.sizeOf(v.TypeOf)
+		if v.OnHeap {
+			return intValue(i.mem.malloc(size), 8)
+		}
+		return intValue(i.mem.alloca(size), 8)
+	case *lir.Load:
+		addr := i.get(f, v.Address).uint()
+		return i.mem.load(addr, i.layout.sizeOf(v.Yields()))
+	case *lir.AccessStructProperty:
+		addr := i.get(f, v.Address).uint()
+		return intValue(addr+i.layout.offsetOf(v.Composite, v.Index), 8)
+	case *lir.PointerOffset:
+		addr := i.get(f, v.Address).uint()
+		offset := i.get(f, v.Offset).int()
+		return intValue(addr+uint64(offset), 8)
+	case *lir.ExtractValue:
+		aggregate := i.get(f, v.Address)
+		offset := i.layout.offsetOf(v.Composite, v.Index)
+		size := i.layout.sizeOf(v.Yields())
+		if offset+size > uint64(len(aggregate)) {
+			throw("member %d is out of range for %s", v.Index, v.Composite.Name)
+		}
+
+		member := make(value, size)
+		copy(member, aggregate[offset:offset+size])
+		return member
+	case *lir.BitCast:
+		return i.get(f, v.Value)
+
+	// Control Flow
+	case *lir.PHI:
+		return i.phi(f, v)
+	case *lir.Call:
+		args := make([]value, len(v.Arguments))
+		for idx, arg := range v.Arguments {
+			args[idx] = i.get(f, arg)
+		}
+
+		return i.call(v.Target, args)
+	case *lir.IndirectCall:
+		id := i.get(f, v.Callee).uint()
+		target, ok := i.functions[id]
+		if !ok {
+			throw("call through invalid function pointer %#x", id)
+		}
+
+		args := make([]value, len(v.Arguments))
+		for idx, arg := range v.Arguments {
+			args[idx] = i.get(f, arg)
+		}
+
+		return i.call(target, args)
+	}
+
+	throw("unsupported value %T", v)
+	return nil
+}
+
+func (i *interpreter) phi(f *frame, v *lir.PHI) value {
+	for _, n := range v.Nodes {
+		if n.Block == f.prev {
+			return i.get(f, n.Value)
+		}
+	}
+
+	if f.prev == nil {
+		throw("phi in the entry block of @%s", f.fn.Name)
+	}
+
+	throw("phi has no value for b%d in @%s", f.prev.Index, f.fn.Name)
+	return nil
+}
+
+func (i *interpreter) divisor(f *frame, v lir.Value) value {
+	r := i.get(f, v)
+	if r.uint() == 0 {
+		throw("integer division by zero")
+	}
+
+	return r
+}
+
+func compareInt(op lir.ICompOp, l, r value) bool {
+	switch op {
+	case lir.EQL:
+		return l.uint() == r.uint()
+	case lir.NEQ:
+		return l.uint() != r.uint()
+	case lir.ULSS:
+		return l.uint() < r.uint()
+	case lir.UGTR:
+		return l.uint() > r.uint()
+	case lir.UGEQ:
+		return l.uint() >= r.uint()
+	case lir.ULEQ:
+		return l.uint() <= r.uint()
+	case lir.SLSS:
+		return l.int() < r.int()
+	case lir.SGTR:
+		return l.int() > r.int()
+	case lir.SGEQ:
+		return l.int() >= r.int()
+	case lir.SLEQ:
+		return l.int() <= r.int()
+	}
+
+	throw("invalid comparison %d", op)
+	return false
+}
+
+// floats are compared in order, any comparison involving NaN is false except inequality
+func compareFloat(op lir.ICompOp, l, r float64) bool {
+	switch op {
+	case lir.EQL:
+		return l == r
+	case lir.NEQ:
+		return l != r
+	case lir.ULSS, lir.SLSS:
+		return l < r
+	case lir.UGTR, lir.SGTR:
+		return l > r
+	case lir.UGEQ, lir.SGEQ:
+		return l >= r
+	case lir.ULEQ, lir.SLEQ:
+		return l <= r
+	}
+
+	throw("invalid comparison %d", op)
+	return false
+}
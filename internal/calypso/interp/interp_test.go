@@ -0,0 +1,200 @@
+package interp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/lir"
+)
+
+const program = `module app::main
+
+composite %app::main::Pair = { i32, int }
+
+fn @app::main::count(int %0) -> int {
+b0:
+	br b1
+b1:
+	%1 = phi [int 0, b0], [%3, b2]
+	%2 = icmp slt %1, %0
+	condbr %2, b2, b3
+b2:
+	%3 = add %1, int 1
+	br b1
+b3:
+	ret %1
+}
+
+fn @app::main::sum(*%app::main::Pair %0) -> int {
+b0:
+	%1 = field %app::main::Pair, %0, 1
+	%2 = load %1
+	%3 = field %app::main::Pair, %0, 0
+	%4 = load %3
+	%5 = icmp eq %4, i32 -1
+	condbr %5, b1, b2
+b1:
+	ret %2
+b2:
+	ret int 0
+}
+
+fn @main() -> i8 {
+b0:
+	%0 = call @app::main::count(int 7)
+	%1 = alloca %app::main::Pair
+	%2 = field %app::main::Pair, %1, 0
+	store i32 -1, %2
+	%3 = field %app::main::Pair, %1, 1
+	store %0, %3
+	%4 = call @app::main::sum(%1)
+	%5 = call @putchar(int 104)
+	%6 = call @putchar(int 105)
+	switch %4, b1 [int 7: b2]
+b1:
+	ret i8 1
+b2:
+	call @exit(%4)
+	ret i8 0
+}
+
+extern fn @exit(int %0) -> void
+
+extern fn @putchar(int %0) -> int
+`
+
+func TestRun(t *testing.T) {
+	exec, err := lir.Parse(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &strings.Builder{}
+	code, err := Run(exec, Options{Stdout: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != 7 {
+		t.Errorf("expected exit code 7, found %d", code)
+	}
+
+	if out.String() != "hi" {
+		t.Errorf("expected output %q, found %q", "hi", out.String())
+	}
+}
+
+func TestRunArgumentsAndStdin(t *testing.T) {
+	input := `module app::main
+
+fn @main(i32 %0, **u8 %1) -> i8 {
+b0:
+	%2 = icmp eq %0, i32 2
+	condbr %2, b1, b3
+b1:
+	%3 = offset %1, int 8
+	%4 = load %3
+	%5 = load %4
+	%6 = icmp eq %5, u8 120
+	condbr %6, b2, b3
+b2:
+	%7 = call @getchar()
+	%8 = icmp eq %7, int 121
+	condbr %8, b4, b3
+b3:
+	ret i8 0
+b4:
+	%9 = call @getchar()
+	%10 = icmp eq %9, int -1
+	condbr %10, b5, b3
+b5:
+	ret i8 1
+}
+
+extern fn @getchar() -> int
+`
+
+	exec, err := lir.Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := Run(exec, Options{Args: []string{"app", "x"}, Stdin: strings.NewReader("y")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != 1 {
+		t.Errorf("expected the program to read its argument & stdin, exited with %d", code)
+	}
+}
+
+func TestRunArithmetic(t *testing.T) {
+	tests := []struct {
+		body string
+		code int
+	}{
+		{"%0 = add i8 127, i8 1\n\t%1 = icmp slt %0, i8 0\n\tcondbr %1, b1, b2\nb1:\n\tret i8 1\nb2:\n\tret i8 0", 1},
+		{"%0 = sdiv int -7, int 2\n\t%1 = add %0, int 10\n\tret %1", 7},
+		{"%0 = srem int -7, int 2\n\t%1 = add %0, int 10\n\tret %1", 9},
+		{"%0 = udiv i8 255, i8 5\n\tret %0", 51},
+		{"%0 = mul int 6, int 7\n\tret %0", 42},
+		{"%0 = ashr i8 -8, i8 1\n\t%1 = sub int 0, int 4\n\t%2 = icmp eq %0, i8 -4\n\tcondbr %2, b1, b2\nb1:\n\tret int 1\nb2:\n\tret int 0", 1},
+		{"%0 = alloca int\n\tstore int 3, %0\n\t%1 = load %0\n\t%2 = shl %1, int 2\n\tret %2", 12},
+	}
+
+	for _, test := range tests {
+		input := "module app::main\n\nfn @app::main::f() -> int {\nb0:\n\t" + test.body + "\n}\n\n" +
+			"fn @main() -> i8 {\nb0:\n\t%0 = call @app::main::f()\n\tcall @exit(%0)\n\tret i8 0\n}\n\nextern fn @exit(int %0) -> void\n"
+
+		exec, err := lir.Parse(input)
+		if err != nil {
+			t.Errorf("unexpected parse error %s\n%s", err, input)
+			continue
+		}
+
+		code, err := Run(exec, Options{})
+		if err != nil {
+			t.Errorf("unexpected error %s\n%s", err, input)
+			continue
+		}
+
+		if code != test.code {
+			t.Errorf("expected exit code %d, found %d\n%s", test.code, code, input)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		body string
+		err  string
+	}{
+		{"%0 = sdiv int 1, int 0\n\tret i8 0", "integer division by zero"},
+		{"%0 = load *int null\n\tret i8 0", "invalid memory access"},
+		{"call @abort()\n\tret i8 0", "program aborted"},
+		{"call @sleep(int 1)\n\tret i8 0", "@sleep is not supported"},
+		{"%0 = call @main()\n\tret %0", "stack overflow"},
+	}
+
+	for _, test := range tests {
+		input := "module app::main\n\nfn @main() -> i8 {\nb0:\n\t" + test.body + "\n}\n\n" +
+			"extern fn @abort() -> void\n\nextern fn @sleep(int %0) -> void\n"
+
+		exec, err := lir.Parse(input)
+		if err != nil {
+			t.Errorf("unexpected parse error %s\n%s", err, input)
+			continue
+		}
+
+		_, err = Run(exec, Options{})
+		if err == nil {
+			t.Errorf("expected error containing %q, ran successfully\n%s", test.err, input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, err)
+		}
+	}
+}
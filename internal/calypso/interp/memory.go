@@ -0,0 +1,294 @@
+package interp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+// Base addresses of each segment, zero is never a valid address so nil pointers can be detected
+const (
+	stackBase uint64 = 0x1000
+	heapBase  uint64 = 1 << 40
+)
+
+/*
+Byte addressable memory, split into a stack that is released as functions return & a heap that grows for the life of the program
+
+Values are laid out without padding, composite members follow one another in declaration order.
+*/
+type memory struct {
+	stack []byte
+	heap  []byte
+}
+
+func (m *memory) alloca(size uint64) uint64 {
+	addr := stackBase + uint64(len(m.stack))
+	m.stack = append(m.stack, make([]byte, size)...)
+	return addr
+}
+
+func (m *memory) malloc(size uint64) uint64 {
+	// keep zero sized allocations distinct
+	if size == 0 {
+		size = 1
+	}
+
+	addr := heapBase + uint64(len(m.heap))
+	m.heap = append(m.heap, make([]byte, size)...)
+	return addr
+}
+
+// returns the stack to a previous height, releasing every allocation made since
+func (m *memory) release(height int) {
+	m.stack = m.stack[:height]
+}
+
+// returns the bytes at an address, panicking with a runtime error when the range is not allocated
+func (m *memory) slice(addr, size uint64) []byte {
+	var segment []byte
+	var offset uint64
+
+	switch {
+	case addr >= heapBase:
+		segment, offset = m.heap, addr-heapBase
+	case addr >= stackBase:
+		segment, offset = m.stack, addr-stackBase
+	default:
+		throw("invalid memory access at address %#x", addr)
+	}
+
+	if offset+size > uint64(len(segment)) {
+		throw("invalid memory access at address %#x, %d byte(s)", addr, size)
+	}
+
+	return segment[offset : offset+size]
+}
+
+func (m *memory) load(addr, size uint64) value {
+	v := make(value, size)
+	copy(v, m.slice(addr, size))
+	return v
+}
+
+func (m *memory) store(addr uint64, v value) {
+	copy(m.slice(addr, uint64(len(v))), v)
+}
+
+// reads a nul terminated string
+func (m *memory) cstring(addr uint64) string {
+	s := []byte{}
+	for {
+		b := m.slice(addr, 1)[0]
+		if b == 0 {
+			return string(s)
+		}
+
+		s = append(s, b)
+		addr++
+	}
+}
+
+// * Values
+
+// the little endian representation of a value in memory
+type value []byte
+
+func intValue(v uint64, size uint64) value {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+
+	out := make(value, size)
+	copy(out, b)
+	return out
+}
+
+func (v value) uint() uint64 {
+	b := make([]byte, 8)
+	copy(b, v)
+	return binary.LittleEndian.Uint64(b)
+}
+
+// sign extends the value from its width
+func (v value) int() int64 {
+	n := len(v) * 8
+	if n == 0 || n >= 64 {
+		return int64(v.uint())
+	}
+
+	shift := 64 - n
+	return int64(v.uint()<<shift) >> shift
+}
+
+func (v value) bool() bool {
+	return v.uint() != 0
+}
+
+func (v value) float() float64 {
+	if len(v) == 4 {
+		return float64(math.Float32frombits(uint32(v.uint())))
+	}
+
+	return math.Float64frombits(v.uint())
+}
+
+func floatValue(f float64, size uint64) value {
+	if size == 4 {
+		return intValue(uint64(math.Float32bits(float32(f))), 4)
+	}
+
+	return intValue(math.Float64bits(f), 8)
+}
+
+func boolValue(b bool) value {
+	if b {
+		return value{1}
+	}
+
+	return value{0}
+}
+
+// * Layout
+
+type layout struct {
+	exec  *lir.Executable
+	sizes map[types.Type]uint64
+}
+
+func (l *layout) sizeOf(t types.Type) uint64 {
+	if s, ok := l.sizes[t]; ok {
+		return s
+	}
+
+	var size uint64
+	switch x := t.Parent().(type) {
+	case *types.Basic:
+		size = sizeOfBasic(x)
+	case *types.Pointer, *types.FunctionSignature, *lir.FunctionPointer:
+		size = 8
+	case *lir.StaticArray:
+		size = uint64(x.Count) * l.sizeOf(x.OfType)
+	case *types.Struct, *types.Enum:
+		c, ok := l.exec.CompositeOf(t)
+		if !ok {
+			// enums without associated values are represented by their discriminant
+			if _, isEnum := x.(*types.Enum); isEnum {
+				size = 1
+				break
+			}
+
+			throw("cannot find composite for %s", t)
+		}
+
+		for _, m := range c.Members {
+			size += l.sizeOf(m)
+		}
+	default:
+		throw("unsupported type %s", t)
+	}
+
+	l.sizes[t] = size
+	return size
+}
+
+// the byte offset of a member within a composite
+func (l *layout) offsetOf(c *lir.Composite, index int) uint64 {
+	if index < 0 || index >= len(c.Members) {
+		throw("member %d is out of range for %s", index, c.Name)
+	}
+
+	offset := uint64(0)
+	for _, m := range c.Members[:index] {
+		offset += l.sizeOf(m)
+	}
+
+	return offset
+}
+
+func sizeOfBasic(t *types.Basic) uint64 {
+	switch t.Literal {
+	case types.Void:
+		return 0
+	case types.Bool, types.Int8, types.UInt8, types.Byte:
+		return 1
+	case types.Int16, types.UInt16:
+		return 2
+	case types.Int32, types.UInt32, types.Char, types.Float, types.FloatLiteral:
+		return 4
+	case types.Int, types.UInt, types.Int64, types.UInt64, types.IntegerLiteral, types.Double, types.NilLiteral, types.String:
+		return 8
+	}
+
+	throw("unsupported type %s", t)
+	return 0
+}
+
+func isFloat(t types.Type) bool {
+	b, ok := t.Parent().(*types.Basic)
+	return ok && (b.Literal == types.Float || b.Literal == types.Double || b.Literal == types.FloatLiteral)
+}
+
+// * Constants
+
+func (l *layout) constant(c *lir.Constant) value {
+	size := l.sizeOf(c.Yields())
+
+	switch v := c.Value.(type) {
+	case nil:
+		return make(value, size)
+	case bool:
+		return boolValue(v)
+	case float64:
+		return floatValue(v, size)
+	case float32:
+		return floatValue(float64(v), size)
+	case int64:
+		if isFloat(c.Yields()) {
+			return floatValue(float64(v), size)
+		}
+		return intValue(uint64(v), size)
+	case int:
+		if isFloat(c.Yields()) {
+			return floatValue(float64(v), size)
+		}
+		return intValue(uint64(v), size)
+	case int32:
+		return intValue(uint64(v), size)
+	case uint64:
+		return intValue(v, size)
+	}
+
+	throw("unsupported constant %v of type %s", c.Value, c.Yields())
+	return nil
+}
+
+// * Errors
+
+// A failure while executing a program, such as an invalid memory access or division by zero
+type RuntimeError struct {
+	Message string
+	Trace   []string // functions being executed, innermost last
+}
+
+// the number of frames included in the message of a runtime error
+const traceLimit = 10
+
+func (e *RuntimeError) Error() string {
+	msg := "runtime error: " + e.Message
+	for i := len(e.Trace) - 1; i >= 0 && i >= len(e.Trace)-traceLimit; i-- {
+		msg += "\n\tin @" + e.Trace[i]
+	}
+
+	if len(e.Trace) > traceLimit {
+		msg += fmt.Sprintf("\n\t... %d more", len(e.Trace)-traceLimit)
+	}
+
+	return msg
+}
+
+func throw(format string, args ...any) {
+	panic(&RuntimeError{Message: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,274 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mantton/calypso/internal/calypso/token"
+)
+
+// A replacement of the runes in [Start, End) of the source of a file
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// the number of runes the lexer may read past the end of a token to decide where it ends, e.g. `1.` in `1.5`
+const lookahead = 2
+
+/*
+Applies an edit to the source of a scanned file & relexes the tokens it affects
+
+Scanning restarts at the end of the last token the edit cannot have changed and stops at the first token following the edit which
+scans as it did before, the tokens & errors after it are reused with their positions moved by the edit.
+*/
+func (f *File) Apply(e Edit) error {
+	if e.Start < 0 || e.Start > e.End || e.End > f.Length {
+		return fmt.Errorf("edit [%d, %d) is out of range of the %d characters in %s", e.Start, e.End, f.Length, f.Name)
+	}
+
+	old := f.Chars
+	tokens := f.Tokens
+	text := []rune(e.Text)
+
+	// 1 - Update Source
+	chars := make([]rune, 0, len(old)-(e.End-e.Start)+len(text))
+	chars = append(chars, old[:e.Start]...)
+	chars = append(chars, text...)
+	chars = append(chars, old[e.End:]...)
+
+	f.Chars = chars
+	f.Length = len(chars)
+
+	l := NewWithMode(f, f.mode)
+
+	if len(tokens) == 0 {
+		// never scanned
+		f.Lines = strings.Split(string(chars), "\n")
+		l.ScanAll()
+		return nil
+	}
+
+	// 2 - Restart at the end of the last token the edit cannot have changed
+	restart := sort.Search(len(tokens), func(i int) bool {
+		return tokens[i].Pos.End+lookahead > e.Start
+	})
+
+	if restart != 0 {
+		l.seek(tokens[restart-1].Pos)
+		l.interpolations = interpolationsOf(tokens[:restart])
+	}
+
+	from := l.cursor
+	f.Lines = spliceLines(f.Lines, old, l.line, from, e)
+
+	// 3 - Relex until a token following the edit scans as it did before
+	delta := len(text) - (e.End - e.Start)
+	window := []token.ScannedToken{}
+	reused := -1
+
+	// the old token to compare against & the number of interpolations open before it
+	next, open := restart, len(l.interpolations)
+
+	for !l.isAtEnd() {
+		inStep := len(l.interpolations) == 0
+		tok := l.scan()
+
+		if tok.Tok == token.IGNORE {
+			continue
+		}
+
+		window = append(window, tok)
+
+		if tok.Pos.Start < e.Start+len(text) {
+			continue
+		}
+
+		for next < len(tokens)-1 && tokens[next].Pos.Start+delta < tok.Pos.Start {
+			open = nesting(tokens[next], open)
+			next++
+		}
+
+		o := tokens[next]
+		if next < len(tokens)-1 && inStep && open == 0 && o.Pos.Start >= e.End && o.Pos.Start+delta == tok.Pos.Start &&
+			o.Tok == tok.Tok && o.Lit == tok.Lit && o.Doc == tok.Doc {
+			reused = next
+			break
+		}
+	}
+
+	// 4 - Splice Tokens & Errors
+	errs := ErrorList{}
+	for _, err := range f.Errors {
+		c, ok := err.(*CompilerError)
+
+		// open interpolations are reported again at the end of the file when it is relexed to the end
+		if !ok || c.Range.Start.Start < from && (reused != -1 || c.Range.End.Start != len(old)) {
+			errs.Add(err)
+		}
+	}
+
+	tokens = append(tokens[:restart:restart], window...)
+
+	if reused == -1 {
+		tokens = append(tokens, l.eof())
+		f.Tokens = tokens
+		f.Errors = append(errs, l.errors...)
+		return nil
+	}
+
+	// the errors of the reused token are taken from before the edit along with the errors following it
+	last := window[len(window)-1]
+	for _, err := range l.errors {
+		if c, ok := err.(*CompilerError); !ok || c.Range.Start.Start < last.Pos.Start {
+			errs.Add(err)
+		}
+	}
+
+	s := newShift(last.Pos, f.Tokens[reused].Pos)
+
+	for _, tok := range f.Tokens[reused+1:] {
+		tok.Pos = s.apply(tok.Pos)
+
+		if len(tok.Trivia) != 0 {
+			trivia := make([]token.ScannedToken, len(tok.Trivia))
+			for i, t := range tok.Trivia {
+				t.Pos = s.apply(t.Pos)
+				trivia[i] = t
+			}
+			tok.Trivia = trivia
+		}
+
+		tokens = append(tokens, tok)
+	}
+
+	for _, err := range f.Errors {
+		if c, ok := err.(*CompilerError); ok && c.Range.Start.Start >= f.Tokens[reused].Pos.Start {
+			moved := *c
+			moved.Range = token.SyntaxRange{Start: s.apply(c.Range.Start), End: s.apply(c.Range.End)}
+			errs.Add(&moved)
+		}
+	}
+
+	f.Tokens = tokens
+	f.Errors = errs
+	return nil
+}
+
+// moves the cursor to the end of a token
+func (l *Lexer) seek(pos token.TokenPosition) {
+	l.cursor = pos.Start
+	l.offset = pos.StartByte
+	l.line = pos.Line
+	l.lineOffset = pos.Offset
+	l.utf16Offset = pos.UTF16Offset
+
+	for l.cursor < pos.End {
+		if l.next() == '\n' {
+			l.newLine()
+		}
+	}
+}
+
+// the interpolations left open by a run of tokens, as the lexer tracks them
+func interpolationsOf(tokens []token.ScannedToken) []interpolation {
+	open := []interpolation{}
+
+	for _, tok := range tokens {
+		n := len(open)
+		from := tok.Pos
+		from.End, from.EndByte = from.Start, from.StartByte
+
+		switch {
+		case tok.Tok == token.STRING_HEAD:
+			open = append(open, interpolation{from: from})
+		case n == 0:
+			continue
+		case tok.Tok == token.STRING_MIDDLE:
+			open[n-1] = interpolation{from: from}
+		case tok.Tok == token.STRING_TAIL:
+			open = open[:n-1]
+		case tok.Tok == token.LPAREN:
+			open[n-1].depth++
+		case tok.Tok == token.RPAREN:
+			open[n-1].depth--
+		}
+	}
+
+	return open
+}
+
+// the number of interpolations open after a token
+func nesting(tok token.ScannedToken, open int) int {
+	switch tok.Tok {
+	case token.STRING_HEAD:
+		return open + 1
+	case token.STRING_TAIL:
+		return open - 1
+	}
+
+	return open
+}
+
+// replaces the lines touched by an edit, line is the line of the rune at cursor which precedes the edit
+func spliceLines(lines []string, old []rune, line, cursor int, e Edit) []string {
+	first := line - 1
+	for _, c := range old[cursor:e.Start] {
+		if c == '\n' {
+			first++
+		}
+	}
+
+	last := first
+	for _, c := range old[e.Start:e.End] {
+		if c == '\n' {
+			last++
+		}
+	}
+
+	start, end := e.Start, e.End
+	for start > 0 && old[start-1] != '\n' {
+		start--
+	}
+
+	for end < len(old) && old[end] != '\n' {
+		end++
+	}
+
+	replaced := strings.Split(string(old[start:e.Start])+e.Text+string(old[e.End:end]), "\n")
+	return append(append(lines[:first:first], replaced...), lines[last+1:]...)
+}
+
+// moves the positions following an edit by the distance a token moved
+type shift struct {
+	line                int // the line the token was on before the edit, the columns of positions on it move with the token
+	lines, runes, bytes int
+	offset, utf16Offset int
+}
+
+func newShift(to, from token.TokenPosition) shift {
+	return shift{
+		line:        from.Line,
+		lines:       to.Line - from.Line,
+		runes:       to.Start - from.Start,
+		bytes:       to.StartByte - from.StartByte,
+		offset:      to.Offset - from.Offset,
+		utf16Offset: to.UTF16Offset - from.UTF16Offset,
+	}
+}
+
+func (s shift) apply(p token.TokenPosition) token.TokenPosition {
+	if p.Line == s.line {
+		p.Offset += s.offset
+		p.UTF16Offset += s.utf16Offset
+	}
+
+	p.Line += s.lines
+	p.Start += s.runes
+	p.End += s.runes
+	p.StartByte += s.bytes
+	p.EndByte += s.bytes
+	return p
+}
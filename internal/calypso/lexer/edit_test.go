@@ -0,0 +1,116 @@
+package lexer
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+const editSource = `module main;
+
+/// the answer
+/// to everything
+fn answer() -> int {
+	/* nested /* block */ comment */
+	let café = 1_000 + 0x2A + 1.5e3;
+	let s = "hello \(name), \(f(x, "\(y)")) 😀";
+	return 'é';
+}
+`
+
+// checks a file relexed after an edit against the edited source scanned from scratch
+func checkEdit(t *testing.T, f *File, e Edit) {
+	t.Helper()
+
+	before := string(f.Chars)
+	if err := f.Apply(e); err != nil {
+		t.Fatal(err)
+	}
+
+	src := []rune(before)
+	expected := NewFileFromStringWithMode(string(src[:e.Start])+e.Text+string(src[e.End:]), f.mode)
+
+	if string(f.Chars) != string(expected.Chars) || f.Length != expected.Length {
+		t.Fatalf("unexpected source after %+v: %q", e, string(f.Chars))
+	}
+
+	if !reflect.DeepEqual(f.Lines, expected.Lines) {
+		t.Fatalf("unexpected lines after %+v\nexpected %q\nfound    %q", e, expected.Lines, f.Lines)
+	}
+
+	if !reflect.DeepEqual(f.Tokens, expected.Tokens) {
+		for i := range expected.Tokens {
+			if i >= len(f.Tokens) || !reflect.DeepEqual(f.Tokens[i], expected.Tokens[i]) {
+				t.Fatalf("unexpected token %d after %+v in %q\nexpected %+v\nfound    %+v", i, e, string(f.Chars), expected.Tokens[i], f.Tokens[min(i, len(f.Tokens)-1)])
+			}
+		}
+		t.Fatalf("unexpected tokens after %+v, found %d expected %d", e, len(f.Tokens), len(expected.Tokens))
+	}
+
+	if f.Errors.String() != expected.Errors.String() {
+		t.Fatalf("unexpected errors after %+v in %q\nexpected %s\nfound    %s", e, string(f.Chars), expected.Errors.String(), f.Errors.String())
+	}
+
+	for i, err := range f.Errors {
+		if err.(*CompilerError).Range != expected.Errors[i].(*CompilerError).Range {
+			t.Fatalf("unexpected error range after %+v, expected %+v, found %+v", e, expected.Errors[i].(*CompilerError).Range, err.(*CompilerError).Range)
+		}
+	}
+}
+
+func TestApplyEdit(t *testing.T) {
+	f := NewFileFromString(editSource)
+
+	edits := []Edit{
+		{Start: 7, End: 11, Text: "app"},                // rename the module
+		{Start: 0, End: 0, Text: "// header\n"},         // insert a line before everything
+		{Start: 40, End: 40, Text: "\n"},                // split a line
+		{Start: 20, End: 21, Text: "/"},                 // edit a doc comment
+		{Start: 60, End: 62, Text: ""},                  // delete across tokens
+		{Start: 0, End: 0, Text: "\"unterminated \\(a"}, // open an interpolation
+		{Start: 0, End: 17, Text: ""},                   // & close it again
+		{Start: 100, End: 100, Text: "/*"},              // comment out the rest
+		{Start: 100, End: 102, Text: ""},                // & restore it
+		{Start: 30, End: 30, Text: "$"},                 // introduce an invalid character
+		{Start: 30, End: 31, Text: ""},                  // & remove it
+		{Start: 0, End: 0, Text: ""},                    // an empty edit
+		{Start: len([]rune(editSource)) - 1, End: len([]rune(editSource)) - 1, Text: "let 名前 = 1."}, // append to the end
+	}
+
+	for _, e := range edits {
+		checkEdit(t, f, e)
+	}
+}
+
+func TestApplyRandomEdits(t *testing.T) {
+	fragments := []string{"", "a", "1", ".", "5", "\"", "\\(", ")", "(", "'", "/", "*", "\n", "é", "😀", " ", "//", "/*", "*/", "///", "-", ">", "_", "e", "\\"}
+	rng := rand.New(rand.NewSource(1))
+	f := NewFileFromString(editSource)
+
+	for i := 0; i < 4000; i++ {
+		start := rng.Intn(f.Length + 1)
+		end := min(f.Length, start+rng.Intn(4))
+
+		text := ""
+		for n := rng.Intn(3); n > 0; n-- {
+			text += fragments[rng.Intn(len(fragments))]
+		}
+
+		checkEdit(t, f, Edit{Start: start, End: end, Text: text})
+
+		// keep the file from drifting too far from the source, alternating between modes
+		if i%100 == 99 {
+			f = NewFileFromStringWithMode(editSource, Mode(i/100%2))
+		}
+	}
+}
+
+func TestApplyInvalidEdit(t *testing.T) {
+	f := NewFileFromString("let a = 1;")
+
+	for _, e := range []Edit{{Start: -1, End: 0}, {Start: 3, End: 2}, {Start: 0, End: 11}} {
+		if err := f.Apply(e); err == nil {
+			t.Errorf("expected %+v to be out of range", e)
+		}
+	}
+}
@@ -14,6 +14,9 @@ type File struct {
 	Path   string
 	Lines  []string
 	Tokens []token.ScannedToken
+	Errors ErrorList // lexical errors found while scanning
+
+	mode Mode // the mode the file was scanned in, edits are relexed in the same mode
 }
 
 func NewFile(path string) (*File, error) {
@@ -38,6 +41,10 @@ func NewFile(path string) (*File, error) {
 }
 
 func NewFileFromString(data string) *File {
+	return NewFileFromStringWithMode(data, 0)
+}
+
+func NewFileFromStringWithMode(data string, mode Mode) *File {
 	input := string(data)
 	lines := strings.Split(input, "\n")
 	chars := []rune(input)
@@ -51,7 +58,7 @@ func NewFileFromString(data string) *File {
 		Tokens: nil,
 	}
 
-	l := New(f)
+	l := NewWithMode(f, mode)
 	l.ScanAll()
 
 	return f
@@ -6,7 +6,9 @@ package lexer
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/mantton/calypso/internal/calypso/token"
 )
@@ -15,37 +17,61 @@ const (
 	eof = -1
 )
 
+// Configures the tokens a lexer produces
+type Mode uint
+
+const (
+	ScanTrivia Mode = 1 << iota // retain whitespace & comments as the trivia of the tokens following them
+)
+
 type Lexer struct {
 	file         *File
 	source       []rune // an array of each rune in the file
 	sourceLength int
 
-	anchor int // the start of the token
-	cursor int // the current position in the source
+	anchor    int                 // the start of the token
+	anchorPos token.TokenPosition // the position of the anchor, used to report errors spanning a token
+	cursor    int                 // the current position in the source
+	offset    int                 // the byte offset of the cursor
+
+	line        int // the current line
+	lineOffset  int // the column of the cursor, in runes
+	utf16Offset int // the column of the cursor, in UTF-16 code units
 
-	line       int // the current line
-	lineOffset int
+	mode           Mode
+	doc            []string             // doc comment lines waiting to be attached to the next token
+	trivia         []token.ScannedToken // trivia waiting to be attached to the next token
+	interpolations []interpolation      // the string interpolations being scanned, innermost last
+	errors         ErrorList
+}
+
+// a `\(` interpolation within a string literal, scanning returns to the string at its closing parenthesis
+type interpolation struct {
+	from  token.TokenPosition // the position of the string segment opening the interpolation
+	depth int                 // the number of parentheses opened within the interpolation
 }
 
 func New(file *File) *Lexer {
-	l := &Lexer{source: file.Chars}
+	return NewWithMode(file, 0)
+}
+
+func NewWithMode(file *File, mode Mode) *Lexer {
+	l := &Lexer{source: file.Chars, mode: mode}
 	l.file = file
 	l.sourceLength = file.Length
 	l.line = 1
 	l.lineOffset = 1
+	l.utf16Offset = 1
 
 	return l
 }
 
+// Scans the file into tokens, invalid input is scanned as ILLEGAL tokens & reported in the Errors of the file
 func (l *Lexer) ScanAll() {
 	tokens := []token.ScannedToken{}
 
 	for !l.isAtEnd() {
-		// at the start of next lexeme, drop anchor
-		l.anchor = l.cursor
-
-		// parse next token
-		tok := l.parseToken()
+		tok := l.scan()
 
 		if tok.Tok == token.IGNORE {
 			continue
@@ -56,8 +82,86 @@ func (l *Lexer) ScanAll() {
 	}
 
 	// Add EOF token
-	tokens = append(tokens, token.ScannedToken{Pos: l.genPosition(), Tok: token.EOF, Lit: "EOF"})
+	tokens = append(tokens, l.eof())
 	l.file.Tokens = tokens
+	l.file.Errors = l.errors
+	l.file.mode = l.mode
+}
+
+// scans the next token, whitespace & comments are scanned as IGNORE tokens
+func (l *Lexer) scan() token.ScannedToken {
+	// at the start of next lexeme, drop anchor
+	l.anchor = l.cursor
+	l.anchorPos = l.mark()
+
+	// parse next token
+	tok := l.parseToken()
+
+	if tok.Tok == token.WHITESPACE || tok.Tok == token.COMMENT {
+		if l.mode&ScanTrivia != 0 {
+			l.addTrivia(tok)
+		}
+
+		tok.Tok = token.IGNORE
+	}
+
+	if tok.Tok == token.IGNORE {
+		return tok
+	}
+
+	if len(l.trivia) != 0 {
+		tok.Trivia = l.trivia
+		l.trivia = nil
+	}
+
+	// doc comments belong to the token that follows them
+	if len(l.doc) != 0 {
+		tok.Doc = strings.Join(l.doc, "\n")
+		l.doc = nil
+	}
+
+	return tok
+}
+
+// reports the interpolations left open at the end of the file & returns the EOF token
+func (l *Lexer) eof() token.ScannedToken {
+	for _, i := range l.interpolations {
+		l.errors.Add(NewError("string interpolation not terminated", token.SyntaxRange{Start: i.from, End: l.mark()}, l.file))
+	}
+
+	return token.ScannedToken{Pos: l.mark(), Tok: token.EOF, Lit: "EOF", Trivia: l.trivia}
+}
+
+// records trivia for the next token, runs of whitespace are merged
+func (l *Lexer) addTrivia(tok token.ScannedToken) {
+	n := len(l.trivia)
+
+	if n != 0 && tok.Tok == token.WHITESPACE && l.trivia[n-1].Tok == token.WHITESPACE {
+		l.trivia[n-1].Lit += tok.Lit
+		l.trivia[n-1].Pos.End = tok.Pos.End
+		l.trivia[n-1].Pos.EndByte = tok.Pos.EndByte
+		return
+	}
+
+	l.trivia = append(l.trivia, tok)
+}
+
+// the position of the cursor
+func (l *Lexer) mark() token.TokenPosition {
+	return token.TokenPosition{
+		Line:        l.line,
+		Offset:      l.lineOffset,
+		Start:       l.cursor,
+		End:         l.cursor,
+		UTF16Offset: l.utf16Offset,
+		StartByte:   l.offset,
+		EndByte:     l.offset,
+	}
+}
+
+// reports an error spanning from a position to the cursor
+func (l *Lexer) error(from token.TokenPosition, message string) {
+	l.errors.Add(NewError(message, token.SyntaxRange{Start: from, End: l.mark()}, l.file))
 }
 
 func (l *Lexer) isAtEnd() bool {
@@ -68,6 +172,12 @@ func (l *Lexer) next() rune {
 	c := l.source[l.cursor]
 	l.cursor++
 	l.lineOffset++
+	l.offset += utf8.RuneLen(c)
+	l.utf16Offset++
+	if c >= 0x10000 {
+		// encoded as a surrogate pair
+		l.utf16Offset++
+	}
 	return c
 }
 
@@ -78,14 +188,30 @@ func (l *Lexer) parseToken() token.ScannedToken {
 	switch c {
 
 	case ' ', '\r', '\t':
-		// Ignore whitespace.
-		tok = l.build(token.IGNORE)
+		tok = l.build(token.WHITESPACE)
 	case '\n':
 		l.newLine()
-		tok = l.build(token.IGNORE)
+		tok = l.build(token.WHITESPACE)
 	case '(':
+		if n := len(l.interpolations); n != 0 {
+			l.interpolations[n-1].depth++
+		}
+
 		tok = l.build(token.LPAREN)
 	case ')':
+		n := len(l.interpolations)
+
+		if n != 0 && l.interpolations[n-1].depth == 0 {
+			// closes the interpolation, the string continues
+			l.interpolations = l.interpolations[:n-1]
+			tok = l.string(false)
+			break
+		}
+
+		if n != 0 {
+			l.interpolations[n-1].depth--
+		}
+
 		tok = l.build(token.RPAREN)
 	case '[':
 		tok = l.build(token.LBRACKET)
@@ -176,11 +302,20 @@ func (l *Lexer) parseToken() token.ScannedToken {
 	case '/':
 		// Comment, match to end of line
 		if l.match('/') {
+			// `///` starts a doc comment, `////` is a regular comment
+			isDoc := l.peek() == '/' && l.peekAhead() != '/'
+
 			for l.peek() != '\n' && !l.isAtEnd() {
 				l.next()
 			}
 
-			tok = l.build(token.IGNORE)
+			if isDoc {
+				l.docComment()
+			}
+
+			tok = l.build(token.COMMENT)
+		} else if l.match('*') {
+			tok = l.blockComment()
 		} else if l.match('=') {
 			tok = l.build(token.QUO_EQ)
 		} else {
@@ -188,7 +323,7 @@ func (l *Lexer) parseToken() token.ScannedToken {
 		}
 	// * Literals
 	case '"':
-		tok = l.string()
+		tok = l.string(true)
 	case '\'':
 		tok = l.char()
 	case '&':
@@ -220,12 +355,47 @@ func (l *Lexer) parseToken() token.ScannedToken {
 			tok = l.number()
 		} else if isLetter(c) {
 			tok = l.identifier()
+		} else {
+			l.error(l.anchorPos, fmt.Sprintf("invalid character %#U", c))
 		}
 	}
 
 	return tok
 }
 
+// records the text of the `///` comment ending at the cursor
+func (l *Lexer) docComment() {
+	text := string(l.source[l.anchor+3 : l.cursor])
+	text = strings.TrimSuffix(text, "\r")
+	text = strings.TrimPrefix(text, " ")
+	l.doc = append(l.doc, text)
+}
+
+// scans a `/* */` comment, comments may be nested
+func (l *Lexer) blockComment() token.ScannedToken {
+	depth := 1
+
+	for depth > 0 {
+		if l.isAtEnd() {
+			l.error(l.anchorPos, "block comment not terminated")
+			return l.build(token.ILLEGAL)
+		}
+
+		switch ch := l.next(); {
+		case ch == '\n':
+			l.newLine()
+		case ch == '/' && l.peek() == '*':
+			l.next()
+			depth++
+		case ch == '*' && l.peek() == '/':
+			l.next()
+			depth--
+		}
+	}
+
+	return l.build(token.COMMENT)
+}
+
 func (l *Lexer) build(t token.Token) token.ScannedToken {
 	return token.ScannedToken{
 		Pos: l.genPosition(),
@@ -243,7 +413,7 @@ func (l *Lexer) match(expected rune) bool {
 		return false
 	}
 
-	l.cursor++
+	l.next()
 	return true
 }
 
@@ -257,6 +427,7 @@ func (l *Lexer) peek() rune {
 
 func (l *Lexer) char() token.ScannedToken {
 	// Reference: https://cs.opensource.google/go/go/+/refs/tags/go1.22.0:src/go/scanner/scanner.go;l=609
+	valid := true
 	n := 0
 
 	for {
@@ -264,10 +435,12 @@ func (l *Lexer) char() token.ScannedToken {
 
 		// if new line or invalid character
 		if ch == '\n' || ch < 0 {
-			panic("char literal not terminated")
+			l.error(l.anchorPos, "char literal not terminated")
+			return l.build(token.ILLEGAL)
 		}
 
 		// Move to next token
+		pos := l.mark()
 		l.next()
 
 		// closing quote
@@ -279,16 +452,21 @@ func (l *Lexer) char() token.ScannedToken {
 		n++
 
 		// Scan/Parse Escape Character
-		if ch == '\\' {
-			l.scanEscape('\'')
+		if ch == '\\' && !l.scanEscape('\'', pos) {
+			valid = false
 		}
 
 		// read to closing quote
 
 	}
 
-	if n != 1 {
-		panic("invalid char literal")
+	if valid && n != 1 {
+		l.error(l.anchorPos, "invalid char literal")
+		valid = false
+	}
+
+	if !valid {
+		return l.build(token.ILLEGAL)
 	}
 
 	s := string(l.source[l.anchor:l.cursor])
@@ -301,12 +479,14 @@ func (l *Lexer) char() token.ScannedToken {
 }
 
 // REFERENCE : https://cs.opensource.google/go/go/+/refs/tags/go1.22.0:src/go/scanner/scanner.go;l=556
-func (l *Lexer) scanEscape(br rune) {
+// Scans the escape sequence following a backslash at from, reporting whether it is valid
+func (l *Lexer) scanEscape(br rune, from token.TokenPosition) bool {
 	var n int
 	var base, max uint32
 	switch l.peek() {
 	case 'a', 'b', 'f', 'n', 'r', 't', 'v', '\\', br:
 		l.next()
+		return true
 	case '0', '1', '2', '3', '4', '5', '6', '7':
 		n, base, max = 3, 8, 255
 	case 'x':
@@ -322,8 +502,12 @@ func (l *Lexer) scanEscape(br rune) {
 		msg := "unknown escape sequence"
 		if l.peek() < 0 {
 			msg = "escape sequence not terminated"
+		} else if l.peek() != '\n' {
+			// include the unknown character in the reported range
+			l.next()
 		}
-		panic(msg)
+		l.error(from, msg)
+		return false
 	}
 
 	var x uint32
@@ -334,7 +518,8 @@ func (l *Lexer) scanEscape(br rune) {
 			if l.peek() < 0 {
 				msg = "escape sequence not terminated"
 			}
-			panic(msg)
+			l.error(from, msg)
+			return false
 		}
 		x = x*base + d
 		l.next()
@@ -342,8 +527,11 @@ func (l *Lexer) scanEscape(br rune) {
 	}
 
 	if x > max || 0xD800 <= x && x < 0xE000 {
-		panic("escape sequence is invalid Unicode code point")
+		l.error(from, "escape sequence is invalid Unicode code point")
+		return false
 	}
+
+	return true
 }
 
 func digitVal(ch rune) int {
@@ -358,26 +546,69 @@ func digitVal(ch rune) int {
 
 func lower(ch rune) rune { return ('a' - 'A') | ch } // returns lower-case ch iff ch is ASCII letter
 
-func (l *Lexer) string() token.ScannedToken {
+/*
+Scans a string literal, or the segment of an interpolated string which follows the `)` closing an interpolation
+
+A segment ends at the closing quote or at the `\(` opening the next interpolation, the expression within is scanned as regular tokens.
+`"a \(x) b \(y) c"` is scanned as STRING_HEAD, x, STRING_MIDDLE, y & STRING_TAIL, the literal of each segment is the source between its delimiters.
+*/
+func (l *Lexer) string(opening bool) token.ScannedToken {
+	valid := true
+	interpolates := false
+
 	for l.peek() != '"' && !l.isAtEnd() {
-		if l.peek() == '\n' {
+		pos := l.mark()
+		ch := l.next()
+
+		if ch == '\n' {
 			l.newLine()
 		}
 
+		if ch != '\\' {
+			continue
+		}
+
+		if l.peek() == '(' {
+			l.next()
+			l.interpolations = append(l.interpolations, interpolation{from: l.anchorPos})
+			interpolates = true
+			break
+		}
+
+		if !l.scanEscape('"', pos) {
+			valid = false
+		}
+	}
+
+	if !interpolates {
+		if l.isAtEnd() {
+			l.error(l.anchorPos, "string literal not terminated")
+			return l.build(token.ILLEGAL)
+		}
+
 		l.next()
 	}
 
-	if l.isAtEnd() {
-		panic("unterminated string")
+	// segments of an interpolated string keep their kind, the nesting of interpolations is recovered from them when relexing
+	if !valid && opening && !interpolates {
+		return l.build(token.ILLEGAL)
 	}
 
-	l.next()
+	tok := token.STRING
+	end := l.cursor - 1
 
-	str := string(l.source[l.anchor+1 : l.cursor-1])
+	switch {
+	case opening && interpolates:
+		tok, end = token.STRING_HEAD, l.cursor-2
+	case interpolates:
+		tok, end = token.STRING_MIDDLE, l.cursor-2
+	case !opening:
+		tok = token.STRING_TAIL
+	}
 
 	return token.ScannedToken{
-		Lit: str,
-		Tok: token.STRING,
+		Lit: string(l.source[l.anchor+1 : end]),
+		Tok: tok,
 		Pos: l.genPosition(),
 	}
 }
@@ -386,24 +617,104 @@ func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
-func (l *Lexer) number() token.ScannedToken {
+var baseNames = map[int]string{
+	2:  "binary",
+	8:  "octal",
+	10: "decimal",
+	16: "hexadecimal",
+}
 
+/*
+Scans a numeric literal, the first digit has been consumed
+
+  - `0x`, `0o` & `0b` prefixes select the base of integers
+  - `_` may separate successive digits, e.g. `1_000_000`
+  - decimal literals may have a fraction & an exponent, e.g. `1.5e-3`
+  - a type suffix may follow, e.g. `10u8` or `3.0f`, suffixes are validated by the parser
+*/
+func (l *Lexer) number() token.ScannedToken {
 	tType := token.INTEGER
-	for isDigit(l.peek()) {
-		l.next()
+	base := 10
+
+	if l.source[l.anchor] == '0' {
+		switch lower(l.peek()) {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+
+		if base != 10 {
+			l.next()
+		}
 	}
 
-	// if the current tok is a period, look at float
-	if l.peek() == '.' && isDigit(l.peekAhead()) {
-		tType = token.FLOAT
+	valid := true
+	invalid := func(msg string) {
+		if valid {
+			l.error(l.anchorPos, msg)
+		}
+		valid = false
+	}
 
-		// consume '.'
-		l.next()
+	// integer part, the leading digit of decimal literals has been consumed
+	run := l.digits(base)
+	if base == 10 {
+		run = string(l.source[l.anchor]) + run
+	}
 
-		for isDigit(l.peek()) {
+	if strings.Trim(run, "_") == "" {
+		invalid(fmt.Sprintf("%s literal has no digits", baseNames[base]))
+	} else if !separated(run, base != 10) {
+		invalid("'_' must separate successive digits")
+	}
+
+	// digits beyond the base, e.g. `0b102`
+	if isDigit(l.peek()) {
+		invalid(fmt.Sprintf("invalid digit %q in %s literal", l.peek(), baseNames[base]))
+		l.digits(10)
+	}
+
+	if base == 10 {
+		// fraction
+		if l.peek() == '.' && isDigit(l.peekAhead()) {
+			tType = token.FLOAT
 			l.next()
+
+			if !separated(l.digits(10), false) {
+				invalid("'_' must separate successive digits")
+			}
+		}
+
+		// exponent
+		if lower(l.peek()) == 'e' {
+			tType = token.FLOAT
+			l.next()
+
+			if l.peek() == '+' || l.peek() == '-' {
+				l.next()
+			}
+
+			exp := l.digits(10)
+			if exp == "" || exp[0] == '_' {
+				invalid("exponent has no digits")
+			} else if !separated(exp, false) {
+				invalid("'_' must separate successive digits")
+			}
 		}
 	}
+
+	// suffix
+	for isAlphaNumeric(l.peek()) {
+		l.next()
+	}
+
+	if !valid {
+		return l.build(token.ILLEGAL)
+	}
+
 	str := string(l.source[l.anchor:l.cursor])
 
 	return token.ScannedToken{
@@ -413,6 +724,31 @@ func (l *Lexer) number() token.ScannedToken {
 	}
 }
 
+// consumes the digits of a base & any separators among them
+func (l *Lexer) digits(base int) string {
+	start := l.cursor
+	for l.peek() == '_' || digitVal(l.peek()) < base {
+		l.next()
+	}
+
+	return string(l.source[start:l.cursor])
+}
+
+// reports whether every `_` in a run of digits is between two digits, or follows a base prefix when leading is allowed
+func separated(run string, leading bool) bool {
+	for i := 0; i < len(run); i++ {
+		if run[i] != '_' {
+			continue
+		}
+
+		if i == len(run)-1 || run[i+1] == '_' || (i == 0 && !leading) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (l *Lexer) peekAhead() rune {
 
 	idx := l.cursor + 1
@@ -425,11 +761,11 @@ func (l *Lexer) peekAhead() rune {
 }
 
 func isLetter(ch rune) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' || ch >= utf8.RuneSelf && unicode.IsLetter(ch)
 }
 
 func isAlphaNumeric(c rune) bool {
-	return isLetter(c) || isDigit(c)
+	return isLetter(c) || isDigit(c) || c >= utf8.RuneSelf && unicode.IsDigit(c)
 }
 
 func (l *Lexer) identifier() token.ScannedToken {
@@ -444,16 +780,17 @@ func (l *Lexer) identifier() token.ScannedToken {
 	return tok
 }
 
+// moves to the next line, called after consuming a line break
 func (l *Lexer) newLine() {
 	l.line++
 	l.lineOffset = 1
+	l.utf16Offset = 1
 }
 
+// the position of the token spanning from the anchor to the cursor
 func (l *Lexer) genPosition() token.TokenPosition {
-	return token.TokenPosition{
-		Line:   l.line,
-		Offset: l.lineOffset,
-		Start:  l.anchor,
-		End:    l.cursor + 1,
-	}
+	pos := l.anchorPos
+	pos.End = l.cursor
+	pos.EndByte = l.offset
+	return pos
 }
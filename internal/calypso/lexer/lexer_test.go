@@ -0,0 +1,312 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/token"
+)
+
+func TestScanErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{`'a`, "char literal not terminated"},
+		{`'ab'`, "invalid char literal"},
+		{`''`, "invalid char literal"},
+		{`'\q'`, "unknown escape sequence"},
+		{`'\xZZ'`, "illegal character U+005A 'Z' in escape sequence"},
+		{`'\uD800'`, "escape sequence is invalid Unicode code point"},
+		{`"abc`, "string literal not terminated"},
+		{`"a\qb"`, "unknown escape sequence"},
+		{`let a = 1 $ 2;`, "invalid character U+0024 '$'"},
+	}
+
+	for _, test := range tests {
+		f := NewFileFromString(test.input)
+
+		if len(f.Errors) != 1 {
+			t.Errorf("expected 1 error for %s, found %d: %s", test.input, len(f.Errors), f.Errors.String())
+			continue
+		}
+
+		if !strings.Contains(f.Errors[0].Error(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, f.Errors[0])
+		}
+	}
+}
+
+func TestScanContinuesAfterErrors(t *testing.T) {
+	input := "let a = '\\q';\nlet b = $;\nlet c = \"ok\";\nlet d = \"unterminated"
+	f := NewFileFromString(input)
+
+	if len(f.Errors) != 3 {
+		t.Fatalf("expected 3 errors, found %d: %s", len(f.Errors), f.Errors.String())
+	}
+
+	lines := []int{1, 2, 4}
+	for i, err := range f.Errors {
+		if line := err.(*CompilerError).Range.Start.Line; line != lines[i] {
+			t.Errorf("expected error %d on line %d, found line %d", i, lines[i], line)
+		}
+	}
+
+	illegal, literals := 0, 0
+	for _, tok := range f.Tokens {
+		switch tok.Tok {
+		case token.ILLEGAL:
+			illegal++
+		case token.STRING:
+			literals++
+		}
+	}
+
+	if illegal != 3 || literals != 1 {
+		t.Errorf("expected 3 illegal tokens & 1 string, found %d & %d", illegal, literals)
+	}
+}
+
+func TestScanEscapes(t *testing.T) {
+	input := `'\n' '\'' '\x41' '\u00e9' "a\"b\\"`
+	f := NewFileFromString(input)
+
+	if len(f.Errors) != 0 {
+		t.Fatal(f.Errors.String())
+	}
+
+	expected := []token.Token{token.CHAR, token.CHAR, token.CHAR, token.CHAR, token.STRING, token.EOF}
+	if len(f.Tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, found %d", len(expected), len(f.Tokens))
+	}
+
+	for i, tok := range f.Tokens {
+		if tok.Tok != expected[i] {
+			t.Errorf("expected token %d to be %s, found %s", i, token.LookUp(expected[i]), token.LookUp(tok.Tok))
+		}
+	}
+}
+
+func TestScanErrorRange(t *testing.T) {
+	f := NewFileFromString(`let s = "a\qb";`)
+
+	if len(f.Errors) != 1 {
+		t.Fatalf("expected 1 error, found %d", len(f.Errors))
+	}
+
+	r := f.Errors[0].(*CompilerError).Range
+	if r.Start.Start != 10 || r.End.Start != 12 {
+		t.Errorf("expected the escape sequence to span [10, 12), found [%d, %d)", r.Start.Start, r.End.Start)
+	}
+}
+
+func TestScanComments(t *testing.T) {
+	input := "a /* b /* c */ d */ e\n/* multi\nline */ f // g\n/// doc\n/// lines\nh"
+	f := NewFileFromString(input)
+
+	if len(f.Errors) != 0 {
+		t.Fatal(f.Errors.String())
+	}
+
+	lits := []string{}
+	for _, tok := range f.Tokens {
+		lits = append(lits, tok.Lit)
+	}
+
+	if s := strings.Join(lits, " "); s != "a e f h EOF" {
+		t.Fatalf("expected tokens a e f h EOF, found %s", s)
+	}
+
+	if f.Tokens[2].Pos.Line != 3 {
+		t.Errorf("expected f on line 3, found line %d", f.Tokens[2].Pos.Line)
+	}
+
+	if f.Tokens[3].Doc != "doc\nlines" {
+		t.Errorf("expected h to carry the doc comment, found %q", f.Tokens[3].Doc)
+	}
+
+	f = NewFileFromString("a /* b /* c */")
+	if len(f.Errors) != 1 || !strings.Contains(f.Errors[0].Error(), "block comment not terminated") {
+		t.Errorf("expected an unterminated block comment, found %s", f.Errors.String())
+	}
+}
+
+func TestScanNumbers(t *testing.T) {
+	tests := []struct {
+		input string
+		tok   token.Token
+	}{
+		{"42", token.INTEGER},
+		{"1_000_000", token.INTEGER},
+		{"0xFF_FF", token.INTEGER},
+		{"0x_ff", token.INTEGER},
+		{"0o755", token.INTEGER},
+		{"0b1010_1010", token.INTEGER},
+		{"10u8", token.INTEGER},
+		{"0xFFi32", token.INTEGER},
+		{"3.14", token.FLOAT},
+		{"1.5e-3", token.FLOAT},
+		{"2E10", token.FLOAT},
+		{"3.0f", token.FLOAT},
+		{"1_0.0_1e+1_0d", token.FLOAT},
+	}
+
+	for _, test := range tests {
+		f := NewFileFromString(test.input)
+
+		if len(f.Errors) != 0 {
+			t.Errorf("unexpected error scanning %s: %s", test.input, f.Errors.String())
+			continue
+		}
+
+		if tok := f.Tokens[0]; tok.Tok != test.tok || tok.Lit != test.input || len(f.Tokens) != 2 {
+			t.Errorf("expected %s to scan as a single %s, found %v", test.input, token.LookUp(test.tok), f.Tokens)
+		}
+	}
+}
+
+func TestScanNumberErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"0x", "hexadecimal literal has no digits"},
+		{"0b_", "binary literal has no digits"},
+		{"0b102", "invalid digit '2' in binary literal"},
+		{"0o78", "invalid digit '8' in octal literal"},
+		{"1__000", "'_' must separate successive digits"},
+		{"1000_", "'_' must separate successive digits"},
+		{"1.5_", "'_' must separate successive digits"},
+		{"1e", "exponent has no digits"},
+		{"1e+_5", "exponent has no digits"},
+	}
+
+	for _, test := range tests {
+		f := NewFileFromString(test.input)
+
+		if len(f.Errors) != 1 {
+			t.Errorf("expected 1 error for %s, found %d: %s", test.input, len(f.Errors), f.Errors.String())
+			continue
+		}
+
+		if !strings.Contains(f.Errors[0].Error(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, f.Errors[0])
+		}
+
+		if f.Tokens[0].Tok != token.ILLEGAL {
+			t.Errorf("expected %s to scan as an illegal token", test.input)
+		}
+	}
+}
+
+func TestScanInterpolation(t *testing.T) {
+	input := `"a \(x) b \(f(y, "\(z)")) c" "\(w)"`
+	f := NewFileFromString(input)
+
+	if len(f.Errors) != 0 {
+		t.Fatal(f.Errors.String())
+	}
+
+	expected := []struct {
+		tok token.Token
+		lit string
+	}{
+		{token.STRING_HEAD, "a "},
+		{token.IDENTIFIER, "x"},
+		{token.STRING_MIDDLE, " b "},
+		{token.IDENTIFIER, "f"},
+		{token.LPAREN, "("},
+		{token.IDENTIFIER, "y"},
+		{token.COMMA, ","},
+		{token.STRING_HEAD, ""},
+		{token.IDENTIFIER, "z"},
+		{token.STRING_TAIL, ""},
+		{token.RPAREN, ")"},
+		{token.STRING_TAIL, " c"},
+		{token.STRING_HEAD, ""},
+		{token.IDENTIFIER, "w"},
+		{token.STRING_TAIL, ""},
+		{token.EOF, "EOF"},
+	}
+
+	if len(f.Tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, found %d: %v", len(expected), len(f.Tokens), f.Tokens)
+	}
+
+	for i, tok := range f.Tokens {
+		if tok.Tok != expected[i].tok || tok.Lit != expected[i].lit {
+			t.Errorf("expected token %d to be %s %q, found %s %q", i, token.LookUp(expected[i].tok), expected[i].lit, token.LookUp(tok.Tok), tok.Lit)
+		}
+	}
+
+	f = NewFileFromString(`"a \(x`)
+	if len(f.Errors) != 1 || !strings.Contains(f.Errors[0].Error(), "string interpolation not terminated") {
+		t.Errorf("expected an unterminated interpolation, found %s", f.Errors.String())
+	}
+
+	f = NewFileFromString(`"a \(x) b`)
+	if len(f.Errors) != 1 || !strings.Contains(f.Errors[0].Error(), "string literal not terminated") {
+		t.Errorf("expected an unterminated string, found %s", f.Errors.String())
+	}
+}
+
+func TestScanUnicodeIdentifiers(t *testing.T) {
+	f := NewFileFromString("let café = naïve + 名前 + x١ + _ø;")
+
+	if len(f.Errors) != 0 {
+		t.Fatal(f.Errors.String())
+	}
+
+	idents := []string{}
+	for _, tok := range f.Tokens {
+		if tok.Tok == token.IDENTIFIER {
+			idents = append(idents, tok.Lit)
+		}
+	}
+
+	if s := strings.Join(idents, " "); s != "café naïve 名前 x١ _ø" {
+		t.Errorf("expected identifiers café naïve 名前 x١ _ø, found %s", s)
+	}
+
+	f = NewFileFromString("let a = ١;")
+	if len(f.Errors) != 1 || !strings.Contains(f.Errors[0].Error(), "invalid character U+0661") {
+		t.Errorf("expected identifiers to not start with a digit, found %s", f.Errors.String())
+	}
+}
+
+func TestScanPositions(t *testing.T) {
+	f := NewFileFromString("let é = \"😀\";\n  x == 名;")
+
+	if len(f.Errors) != 0 {
+		t.Fatal(f.Errors.String())
+	}
+
+	expected := []struct {
+		lit                            string
+		line, offset, utf16            int
+		start, end, startByte, endByte int
+	}{
+		{"let", 1, 1, 1, 0, 3, 0, 3},
+		{"é", 1, 5, 5, 4, 5, 4, 6},
+		{"=", 1, 7, 7, 6, 7, 7, 8},
+		{"😀", 1, 9, 9, 8, 11, 9, 15},
+		{";", 1, 12, 13, 11, 12, 15, 16},
+		{"x", 2, 3, 3, 15, 16, 19, 20},
+		{"==", 2, 5, 5, 17, 19, 21, 23},
+		{"名", 2, 8, 8, 20, 21, 24, 27},
+		{";", 2, 9, 9, 21, 22, 27, 28},
+		{"EOF", 2, 10, 10, 22, 22, 28, 28},
+	}
+
+	if len(f.Tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, found %d", len(expected), len(f.Tokens))
+	}
+
+	for i, tok := range f.Tokens {
+		e, p := expected[i], tok.Pos
+		if tok.Lit != e.lit || p.Line != e.line || p.Offset != e.offset || p.UTF16Offset != e.utf16 ||
+			p.Start != e.start || p.End != e.end || p.StartByte != e.startByte || p.EndByte != e.endByte {
+			t.Errorf("unexpected position for %q: %+v", tok.Lit, p)
+		}
+	}
+}
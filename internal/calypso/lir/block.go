@@ -15,7 +15,8 @@ func (b *Block) Emit(i Instruction) {
 
 	b.Instructions = append(b.Instructions, i)
 
-	if _, ok := i.(*Return); ok {
+	switch i.(type) {
+	case *Return, *ReturnVoid:
 		b.Complete = true
 	}
 }
@@ -0,0 +1,17 @@
+package lir
+
+import "testing"
+
+func TestReturnVoidCompletesBlock(t *testing.T) {
+	blk := &Block{}
+	blk.Emit(&ReturnVoid{})
+	blk.Emit(&ReturnVoid{})
+
+	if !blk.Complete {
+		t.Errorf("expected ret void to complete the block")
+	}
+
+	if len(blk.Instructions) != 1 {
+		t.Errorf("expected instructions following ret void to be dropped, found %d instructions", len(blk.Instructions))
+	}
+}
@@ -30,6 +30,14 @@ func (c *Composite) String() string {
 
 }
 
+// Tuples are represented by anonymous composites of their elements
+func NewTupleComposite(t *types.Tuple) *Composite {
+	return &Composite{
+		Members: t.Elements,
+		Type:    t,
+	}
+}
+
 func (c *Composite) Yields() types.Type {
 	return c.Type
 }
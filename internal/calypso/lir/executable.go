@@ -29,6 +29,19 @@ func NewExecutable() *Executable {
 	}
 }
 
+// Returns the composite representing a type, tuples are represented by anonymous composites that are not registered
+func (p Executable) CompositeOf(t types.Type) (*Composite, bool) {
+	if c, ok := p.Composites[t]; ok {
+		return c, true
+	}
+
+	if t, ok := types.ResolveAliases(t).(*types.Tuple); ok {
+		return NewTupleComposite(t), true
+	}
+
+	return nil, false
+}
+
 func (p Executable) GetNestedFunctions(fn *Function) []*Function {
 	var dependencies []*Function
 
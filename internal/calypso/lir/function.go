@@ -51,6 +51,19 @@ func (f *Function) Emit(i Instruction) {
 	f.CurrentBlock.Emit(i)
 }
 
+// reports whether the instruction has been emitted into a block of the function
+func (f *Function) Emitted(i Instruction) bool {
+	for _, b := range f.Blocks {
+		for _, x := range b.Instructions {
+			if x == i {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (f *Function) NewBlock() *Block {
 	b := &Block{
 		Parent: f,
@@ -0,0 +1,21 @@
+package lir
+
+import (
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+/*
+The type of the function pointer held by the environment of a closure
+
+Values of a function type point to an environment, a composite whose first member is a function pointer followed by the values the closure captured.
+The function is called with the environment, followed by the arguments of its signature.
+*/
+type FunctionPointer struct {
+	Signature *types.FunctionSignature
+}
+
+func (t *FunctionPointer) Parent() types.Type { return t }
+
+func (t *FunctionPointer) String() string {
+	return "fnptr" + t.Signature.String()
+}
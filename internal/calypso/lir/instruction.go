@@ -66,6 +66,12 @@ type Call struct {
 	Arguments []Value
 }
 
+// calls the function pointer of a closure
+type IndirectCall struct {
+	Callee    Value // yields a FunctionPointer
+	Arguments []Value
+}
+
 type Return struct {
 	Result Value
 }
@@ -235,6 +241,12 @@ type ExtractValue struct {
 	Composite *Composite
 }
 
+// reinterprets a pointer as a pointer of another type
+type BitCast struct {
+	Value Value
+	To    types.Type
+}
+
 var UOpMap = map[token.Token]ICompOp{
 	token.L_CHEVRON: ULSS,
 	token.R_CHEVRON: UGTR,
@@ -260,6 +272,14 @@ func (c *AccessStructProperty) Yields() types.Type {
 	return types.NewPointer(c.Composite.Members[c.Index])
 }
 
+func (c *IndirectCall) Yields() types.Type {
+	if ptr, ok := c.Callee.Yields().(*FunctionPointer); ok {
+		return ptr.Signature.Result.Type()
+	}
+
+	return types.LookUp(types.Unresolved)
+}
+
 func (c *PointerOffset) Yields() types.Type {
 	return c.Address.Yields()
 }
@@ -291,3 +311,96 @@ func (c *AND) Yields() types.Type                  { return c.Left.Yields() }
 func (c *OR) Yields() types.Type                   { return c.Left.Yields() }
 func (c *PHI) Yields() types.Type                  { return c.Nodes[0].Value.Yields() }
 func (c *ExtractValue) Yields() types.Type         { return c.Composite.Members[c.Index] }
+func (c *BitCast) Yields() types.Type              { return c.To }
+
+// Returns the addresses of the values an instruction consumes, allowing operands to be inspected or replaced
+func Operands(i Instruction) []*Value {
+	switch i := i.(type) {
+	case *Load:
+		return []*Value{&i.Address}
+	case *Store:
+		return []*Value{&i.Value, &i.Address}
+	case *Call:
+		ops := []*Value{}
+		for j := range i.Arguments {
+			ops = append(ops, &i.Arguments[j])
+		}
+		return ops
+	case *IndirectCall:
+		ops := []*Value{&i.Callee}
+		for j := range i.Arguments {
+			ops = append(ops, &i.Arguments[j])
+		}
+		return ops
+	case *Return:
+		return []*Value{&i.Result}
+	case *ConditionalBranch:
+		return []*Value{&i.Condition}
+	case *Switch:
+		ops := []*Value{&i.Value}
+		for _, c := range i.Blocks {
+			ops = append(ops, &c.Value)
+		}
+		return ops
+	case *PHI:
+		ops := []*Value{}
+		for _, n := range i.Nodes {
+			ops = append(ops, &n.Value)
+		}
+		return ops
+	case *AccessStructProperty:
+		return []*Value{&i.Address}
+	case *ExtractValue:
+		return []*Value{&i.Address}
+	case *PointerOffset:
+		return []*Value{&i.Address, &i.Offset}
+	case *BitCast:
+		return []*Value{&i.Value}
+	case *INeg:
+		return []*Value{&i.Right}
+	case *FNeg:
+		return []*Value{&i.Right}
+	case *ICmp:
+		return []*Value{&i.Left, &i.Right}
+	case *FCmp:
+		return []*Value{&i.Left, &i.Right}
+	case *Add:
+		return []*Value{&i.Left, &i.Right}
+	case *FAdd:
+		return []*Value{&i.Left, &i.Right}
+	case *Sub:
+		return []*Value{&i.Left, &i.Right}
+	case *FSub:
+		return []*Value{&i.Left, &i.Right}
+	case *Mul:
+		return []*Value{&i.Left, &i.Right}
+	case *FMul:
+		return []*Value{&i.Left, &i.Right}
+	case *UDiv:
+		return []*Value{&i.Left, &i.Right}
+	case *SDiv:
+		return []*Value{&i.Left, &i.Right}
+	case *FDiv:
+		return []*Value{&i.Left, &i.Right}
+	case *URem:
+		return []*Value{&i.Left, &i.Right}
+	case *SRem:
+		return []*Value{&i.Left, &i.Right}
+	case *FRem:
+		return []*Value{&i.Left, &i.Right}
+	case *XOR:
+		return []*Value{&i.Left, &i.Right}
+	case *ShiftLeft:
+		return []*Value{&i.Left, &i.Right}
+	case *ArithmeticShiftRight:
+		return []*Value{&i.Left, &i.Right}
+	case *LogicalShiftRight:
+		return []*Value{&i.Left, &i.Right}
+	case *AND:
+		return []*Value{&i.Left, &i.Right}
+	case *OR:
+		return []*Value{&i.Left, &i.Right}
+	}
+
+	return nil
+}
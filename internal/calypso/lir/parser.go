@@ -0,0 +1,1505 @@
+package lir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/fs"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+/*
+Parses the textual form written by PrintExecutable back into an executable
+
+	module app::main
+
+	composite %app::main::Point = { int, int }
+
+	fn @app::main::add(int %a, int %b) -> int {
+	entry:
+		%sum = add %a, %b
+		ret %sum
+	}
+
+Values & blocks may be given any name, they are resolved per function & may be referenced before they are defined.
+Names which are not made up of identifier characters are quoted, `@"std::Box::_G::literal int"`.
+*/
+func Parse(input string) (*Executable, error) {
+	tokens, err := scan(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens:     tokens,
+		exec:       NewExecutable(),
+		packages:   make(map[string]*Package),
+		modules:    make(map[string]*Module),
+		composites: make(map[string]*Composite),
+		functions:  make(map[string]*Function),
+		globals:    make(map[string]*Global),
+		tpackages:  make(map[*Package]*types.Package),
+		refs:       make(map[string]lirToken),
+		defined:    make(map[string]bool),
+	}
+
+	err = p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.exec, nil
+}
+
+// * Scanner
+
+type lirTokenKind byte
+
+const (
+	tEOF lirTokenKind = iota
+	tName
+	tLocal  // %name
+	tGlobal // @name
+	tNumber
+	tPunct
+)
+
+type lirToken struct {
+	kind      lirTokenKind
+	lit       string
+	quoted    bool // a quoted name, string constants are always quoted
+	line, col int
+}
+
+func (t lirToken) String() string {
+	switch t.kind {
+	case tEOF:
+		return "end of input"
+	case tLocal:
+		return "%" + t.lit
+	case tGlobal:
+		return "@" + t.lit
+	}
+
+	return t.lit
+}
+
+type scanner struct {
+	input     []rune
+	cursor    int
+	line, col int
+}
+
+func scan(input string) ([]lirToken, error) {
+	s := &scanner{input: []rune(input), line: 1, col: 1}
+	tokens := []lirToken{}
+
+	for {
+		tok, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, tok)
+
+		if tok.kind == tEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (s *scanner) peek(n int) rune {
+	if s.cursor+n >= len(s.input) {
+		return 0
+	}
+
+	return s.input[s.cursor+n]
+}
+
+func (s *scanner) advance() rune {
+	r := s.input[s.cursor]
+	s.cursor++
+
+	if r == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+
+	return r
+}
+
+func (s *scanner) next() (lirToken, error) {
+	// whitespace & comments
+	for s.cursor < len(s.input) {
+		r := s.peek(0)
+		if r == ';' {
+			for s.cursor < len(s.input) && s.peek(0) != '\n' {
+				s.advance()
+			}
+		} else if unicode.IsSpace(r) {
+			s.advance()
+		} else {
+			break
+		}
+	}
+
+	tok := lirToken{line: s.line, col: s.col}
+
+	if s.cursor >= len(s.input) {
+		tok.kind = tEOF
+		return tok, nil
+	}
+
+	r := s.peek(0)
+	switch {
+	case r == '%' || r == '@':
+		s.advance()
+		tok.kind = tLocal
+		if r == '@' {
+			tok.kind = tGlobal
+		}
+
+		name, err := s.name(true)
+		if err != nil {
+			return tok, err
+		}
+
+		tok.lit = name
+	case r == '"' || (r != ':' && isNameRune(r, true)):
+		tok.quoted = r == '"'
+		name, err := s.name(false)
+		if err != nil {
+			return tok, err
+		}
+
+		tok.kind = tName
+		tok.lit = name
+	case unicode.IsDigit(r) || ((r == '-' || r == '+') && unicode.IsDigit(s.peek(1))):
+		tok.kind = tNumber
+		tok.lit = s.number()
+	case r == '-' && s.peek(1) == '>':
+		s.advance()
+		s.advance()
+		tok.kind = tPunct
+		tok.lit = "->"
+	case strings.ContainsRune("(){}[],=:*", r):
+		s.advance()
+		tok.kind = tPunct
+		tok.lit = string(r)
+	default:
+		return tok, fmt.Errorf("%d:%d -> unexpected character %q", tok.line, tok.col, r)
+	}
+
+	return tok, nil
+}
+
+// scans a plain or quoted name, a single `:` ends a name so labels can be told apart from paths
+func (s *scanner) name(leadingDigit bool) (string, error) {
+	line, col := s.line, s.col
+
+	if s.peek(0) == '"' {
+		start := s.cursor
+		s.advance()
+		for s.cursor < len(s.input) && s.peek(0) != '"' && s.peek(0) != '\n' {
+			if s.peek(0) == '\\' {
+				s.advance()
+			}
+			s.advance()
+		}
+
+		if s.cursor >= len(s.input) || s.peek(0) != '"' {
+			return "", fmt.Errorf("%d:%d -> unterminated quoted name", line, col)
+		}
+
+		s.advance()
+		name, err := strconv.Unquote(string(s.input[start:s.cursor]))
+		if err != nil {
+			return "", fmt.Errorf("%d:%d -> invalid quoted name", line, col)
+		}
+
+		return name, nil
+	}
+
+	start := s.cursor
+	for s.cursor < len(s.input) {
+		r := s.peek(0)
+
+		if r == ':' {
+			if s.peek(1) != ':' {
+				break
+			}
+
+			s.advance()
+			s.advance()
+			continue
+		}
+
+		if !isNameRune(r, s.cursor == start && !leadingDigit) {
+			break
+		}
+
+		s.advance()
+	}
+
+	if s.cursor == start {
+		return "", fmt.Errorf("%d:%d -> expected name", line, col)
+	}
+
+	return string(s.input[start:s.cursor]), nil
+}
+
+func (s *scanner) number() string {
+	start := s.cursor
+	s.advance()
+
+	for s.cursor < len(s.input) {
+		r := s.peek(0)
+		prev := s.input[s.cursor-1]
+
+		if unicode.IsDigit(r) || r == '.' || r == 'e' || r == 'E' {
+			s.advance()
+		} else if (r == '-' || r == '+') && (prev == 'e' || prev == 'E') {
+			s.advance()
+		} else {
+			break
+		}
+	}
+
+	return string(s.input[start:s.cursor])
+}
+
+// * Parser
+
+type parser struct {
+	tokens []lirToken
+	cursor int
+
+	exec       *Executable
+	packages   map[string]*Package
+	modules    map[string]*Module
+	composites map[string]*Composite
+	functions  map[string]*Function
+	globals    map[string]*Global
+	tpackages  map[*Package]*types.Package
+	refs       map[string]lirToken // first reference of a composite or function, for reporting undefined names
+	defined    map[string]bool     // defined composites & functions, keyed by their sigil & name
+	calls      []pendingCall       // checked once every function has been declared
+
+	mod *Module
+
+	// function scope
+	fn      *Function
+	values  map[string]Value
+	blocks  map[string]*Block
+	labels  map[string]lirToken // first reference of a block
+	forward map[string]*forwardValue
+}
+
+type pendingCall struct {
+	tok      lirToken
+	call     *Call
+	assigned bool
+}
+
+// a value referenced before it is defined, replaced once the function has been parsed
+type forwardValue struct {
+	tok lirToken
+}
+
+func (v *forwardValue) Yields() types.Type { return types.LookUp(types.Unresolved) }
+
+func (p *parser) current() lirToken {
+	return p.tokens[p.cursor]
+}
+
+func (p *parser) peek() lirToken {
+	if p.cursor+1 >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.cursor+1]
+}
+
+func (p *parser) next() lirToken {
+	tok := p.current()
+	if tok.kind != tEOF {
+		p.cursor++
+	}
+
+	return tok
+}
+
+func (p *parser) is(kind lirTokenKind, lit string) bool {
+	tok := p.current()
+	return tok.kind == kind && (lit == "" || tok.lit == lit)
+}
+
+func (p *parser) expect(kind lirTokenKind, lit string) (lirToken, error) {
+	tok := p.current()
+
+	if !p.is(kind, lit) {
+		expected := lit
+		if expected == "" {
+			expected = map[lirTokenKind]string{
+				tName:   "name",
+				tLocal:  "%name",
+				tGlobal: "@name",
+				tNumber: "number",
+			}[kind]
+		}
+
+		return tok, p.errorf(tok, "expected `%s`, found `%s`", expected, tok)
+	}
+
+	return p.next(), nil
+}
+
+func (p *parser) errorf(tok lirToken, format string, args ...any) error {
+	return fmt.Errorf("%d:%d -> %s", tok.line, tok.col, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parse() error {
+	for !p.is(tEOF, "") {
+		err := p.parseModule()
+		if err != nil {
+			return err
+		}
+	}
+
+	// every referenced composite & function must be defined
+	for key, tok := range p.refs {
+		if !p.defined[key] {
+			return p.errorf(tok, "undefined %s", key)
+		}
+	}
+
+	// calls yielding void do not define a value, all others must
+	for _, c := range p.calls {
+		void := isVoidCall(c.call)
+
+		if c.assigned && void {
+			return p.errorf(c.tok, "call to @%s yields void and cannot define %%%s", c.call.Target.Name, c.tok.lit)
+		}
+
+		if !c.assigned && !void {
+			return p.errorf(c.tok, "result of call to @%s must be assigned", c.call.Target.Name)
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseModule() error {
+	_, err := p.expect(tName, "module")
+	if err != nil {
+		return err
+	}
+
+	tok, err := p.expect(tName, "")
+	if err != nil {
+		return err
+	}
+
+	pkgName, modName, ok := strings.Cut(tok.lit, "::")
+	if !ok {
+		return p.errorf(tok, "expected module name of the form `package::module`, found `%s`", tok.lit)
+	}
+
+	if _, ok := p.modules[tok.lit]; ok {
+		return p.errorf(tok, "redefinition of module %s", tok.lit)
+	}
+
+	pkg, ok := p.packages[pkgName]
+	if !ok {
+		config := &fs.Config{}
+		config.Package.Name = pkgName
+		pkg = NewPackage(ast.NewPackage(fs.NewPackage("", config)))
+		p.packages[pkgName] = pkg
+		p.tpackages[pkg] = types.NewPackage(pkg.AST)
+		p.exec.Packages[pkg.ID()] = pkg
+	}
+
+	aMod := ast.NewModule(nil, pkg.AST)
+	aMod.Set = &ast.FileSet{ModuleName: modName}
+	pkg.AST.AddModule(aMod)
+
+	p.mod = NewModule(types.NewModule(aMod, p.tpackages[pkg]))
+	p.modules[tok.lit] = p.mod
+	pkg.Modules[p.mod.ID()] = p.mod
+	p.exec.Modules[p.mod.ID()] = p.mod
+
+	for !p.is(tEOF, "") && !p.is(tName, "module") {
+		var err error
+		tok := p.current()
+
+		switch {
+		case p.is(tName, "composite"):
+			err = p.parseComposite()
+		case p.is(tName, "global"):
+			err = p.parseGlobal()
+		case p.is(tName, "fn"), p.is(tName, "extern"):
+			err = p.parseFunction()
+		default:
+			err = p.errorf(tok, "expected declaration, found `%s`", tok)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// * Declarations
+
+func (p *parser) parseComposite() error {
+	p.next()
+
+	tok, err := p.expect(tLocal, "")
+	if err != nil {
+		return err
+	}
+
+	key := "%" + tok.lit
+	if p.defined[key] {
+		return p.errorf(tok, "redefinition of composite %s", key)
+	}
+
+	_, err = p.expect(tPunct, "=")
+	if err != nil {
+		return err
+	}
+
+	_, err = p.expect(tPunct, "{")
+	if err != nil {
+		return err
+	}
+
+	members := []types.Type{}
+	for !p.is(tPunct, "}") {
+		if len(members) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return err
+			}
+		}
+
+		t, err := p.parseType()
+		if err != nil {
+			return err
+		}
+
+		members = append(members, t)
+	}
+
+	p.next()
+
+	fields := []*types.Var{}
+	for i, m := range members {
+		fields = append(fields, types.NewVar(strconv.Itoa(i), m, p.mod.TModule))
+	}
+
+	c := p.composite(tok)
+	c.Members = members
+	types.AsDefined(c.Type).SetType(types.NewStruct(fields))
+
+	p.defined[key] = true
+	p.mod.Composites[c.Name] = c
+	p.exec.Composites[c.Type] = c
+	return nil
+}
+
+// returns the composite with the provided name, composites may be referenced before they are defined
+func (p *parser) composite(tok lirToken) *Composite {
+	if c, ok := p.composites[tok.lit]; ok {
+		return c
+	}
+
+	c := &Composite{
+		Name: tok.lit,
+		Type: types.NewBaseDefinedType(tok.lit, nil, nil, nil, p.mod.TModule),
+	}
+
+	p.composites[tok.lit] = c
+	p.refs["%"+tok.lit] = tok
+	return c
+}
+
+func (p *parser) parseGlobal() error {
+	p.next()
+
+	tok, err := p.expect(tGlobal, "")
+	if err != nil {
+		return err
+	}
+
+	if _, ok := p.globals[tok.lit]; ok {
+		return p.errorf(tok, "redefinition of global @%s", tok.lit)
+	}
+
+	_, err = p.expect(tPunct, "=")
+	if err != nil {
+		return err
+	}
+
+	c, err := p.parseConstant()
+	if err != nil {
+		return err
+	}
+
+	g := &Global{Value: c}
+	p.globals[tok.lit] = g
+	p.mod.GlobalConstants[tok.lit] = g
+	return nil
+}
+
+// returns the function with the provided name, functions may be referenced before they are defined
+func (p *parser) function(tok lirToken) *Function {
+	if fn, ok := p.functions[tok.lit]; ok {
+		return fn
+	}
+
+	fn := &Function{
+		Name:      tok.lit,
+		Variables: make(map[string]Value),
+		id:        atomic.AddInt64(&tick, 1),
+	}
+
+	p.functions[tok.lit] = fn
+	p.refs["@"+tok.lit] = tok
+	return fn
+}
+
+func (p *parser) parseFunction() error {
+	external := false
+	if p.is(tName, "extern") {
+		p.next()
+		external = true
+	}
+
+	_, err := p.expect(tName, "fn")
+	if err != nil {
+		return err
+	}
+
+	tok, err := p.expect(tGlobal, "")
+	if err != nil {
+		return err
+	}
+
+	key := "@" + tok.lit
+	if p.defined[key] {
+		return p.errorf(tok, "redefinition of function %s", key)
+	}
+
+	fn := p.function(tok)
+	fn.External = external
+
+	p.fn = fn
+	p.values = make(map[string]Value)
+	p.blocks = make(map[string]*Block)
+	p.labels = make(map[string]lirToken)
+	p.forward = make(map[string]*forwardValue)
+
+	// Signature
+	sg := types.NewFunctionSignature()
+
+	_, err = p.expect(tPunct, "(")
+	if err != nil {
+		return err
+	}
+
+	for !p.is(tPunct, ")") {
+		if len(fn.Parameters) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return err
+			}
+		}
+
+		t, err := p.parseType()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expect(tLocal, "")
+		if err != nil {
+			return err
+		}
+
+		param := &Parameter{
+			Name:   name.lit,
+			Symbol: t,
+			Parent: fn,
+		}
+
+		err = p.define(name, param)
+		if err != nil {
+			return err
+		}
+
+		sg.AddParameter(types.NewVar(name.lit, t, p.mod.TModule))
+		fn.Parameters = append(fn.Parameters, param)
+		fn.Variables[name.lit] = param
+	}
+
+	p.next()
+
+	_, err = p.expect(tPunct, "->")
+	if err != nil {
+		return err
+	}
+
+	result, err := p.parseType()
+	if err != nil {
+		return err
+	}
+
+	sg.Result.SetType(result)
+	fn.TFunction = types.NewFunction(tok.lit, sg, p.mod.TModule)
+
+	p.defined[key] = true
+	p.mod.Functions[fn.Name] = fn
+	p.exec.Functions[sg] = fn
+
+	if external {
+		return nil
+	}
+
+	// Body
+	_, err = p.expect(tPunct, "{")
+	if err != nil {
+		return err
+	}
+
+	for !p.is(tPunct, "}") {
+		if p.is(tName, "") && p.peek().kind == tPunct && p.peek().lit == ":" {
+			err = p.parseLabel()
+		} else if fn.CurrentBlock == nil {
+			err = p.errorf(p.current(), "expected block label, found `%s`", p.current())
+		} else {
+			err = p.parseInstruction()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	p.next()
+	return p.resolveFunction()
+}
+
+// replaces forward references once every value & block of a function has been defined
+func (p *parser) resolveFunction() error {
+	for label, blk := range p.blocks {
+		if blk.Index < 0 {
+			return p.errorf(p.labels[label], "undefined block %s", label)
+		}
+	}
+
+	for _, blk := range p.fn.Blocks {
+		for _, i := range blk.Instructions {
+			for _, op := range Operands(i) {
+				fv, ok := (*op).(*forwardValue)
+				if !ok {
+					continue
+				}
+
+				v, ok := p.values[fv.tok.lit]
+				if !ok {
+					return p.errorf(fv.tok, "undefined value %%%s", fv.tok.lit)
+				}
+
+				*op = v
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseLabel() error {
+	tok := p.next()
+	p.next()
+
+	blk := p.block(tok)
+	if blk.Index >= 0 {
+		return p.errorf(tok, "redefinition of block %s", tok.lit)
+	}
+
+	p.fn.Blocks = append(p.fn.Blocks, blk)
+	blk.Index = len(p.fn.Blocks) - 1
+	p.fn.CurrentBlock = blk
+	return nil
+}
+
+// returns the block with the provided label, blocks may be referenced before they are defined
+func (p *parser) block(tok lirToken) *Block {
+	if blk, ok := p.blocks[tok.lit]; ok {
+		return blk
+	}
+
+	blk := &Block{
+		Index:  -1,
+		Parent: p.fn,
+	}
+
+	p.blocks[tok.lit] = blk
+	p.labels[tok.lit] = tok
+	return blk
+}
+
+func (p *parser) parseBlockRef() (*Block, error) {
+	tok, err := p.expect(tName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return p.block(tok), nil
+}
+
+func (p *parser) define(tok lirToken, v Value) error {
+	if _, ok := p.values[tok.lit]; ok {
+		return p.errorf(tok, "redefinition of value %%%s", tok.lit)
+	}
+
+	p.values[tok.lit] = v
+	delete(p.forward, tok.lit)
+	return nil
+}
+
+// * Instructions
+
+func (p *parser) emit(tok lirToken, i Instruction) error {
+	if p.fn.CurrentBlock.Complete {
+		return p.errorf(tok, "instruction after return in block")
+	}
+
+	p.fn.CurrentBlock.Emit(i)
+	return nil
+}
+
+func (p *parser) parseInstruction() error {
+	tok := p.current()
+
+	// %name = <value>
+	if tok.kind == tLocal {
+		p.next()
+
+		_, err := p.expect(tPunct, "=")
+		if err != nil {
+			return err
+		}
+
+		v, err := p.parseValueInstruction()
+		if err != nil {
+			return err
+		}
+
+		if call, ok := v.(*Call); ok {
+			p.calls = append(p.calls, pendingCall{tok: tok, call: call, assigned: true})
+		}
+
+		err = p.define(tok, v)
+		if err != nil {
+			return err
+		}
+
+		return p.emit(tok, v)
+	}
+
+	var instr Instruction
+	var err error
+
+	switch {
+	case p.is(tName, "store"):
+		instr, err = p.parseStore()
+	case p.is(tName, "ret"):
+		instr, err = p.parseReturn()
+	case p.is(tName, "br"):
+		p.next()
+		var blk *Block
+		blk, err = p.parseBlockRef()
+		instr = &Branch{Block: blk}
+	case p.is(tName, "condbr"):
+		instr, err = p.parseConditionalBranch()
+	case p.is(tName, "switch"):
+		instr, err = p.parseSwitch()
+	case p.is(tName, "call"):
+		var v Value
+		v, err = p.parseValueInstruction()
+		if err == nil {
+			p.calls = append(p.calls, pendingCall{tok: tok, call: v.(*Call)})
+		}
+		instr = v
+	case p.is(tName, "call.ptr"):
+		instr, err = p.parseValueInstruction()
+	default:
+		return p.errorf(tok, "expected instruction, found `%s`", tok)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return p.emit(tok, instr)
+}
+
+func (p *parser) parseStore() (Instruction, error) {
+	p.next()
+
+	val, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{Value: val, Address: addr}, nil
+}
+
+func (p *parser) parseReturn() (Instruction, error) {
+	p.next()
+
+	if p.is(tName, "void") {
+		p.next()
+		return &ReturnVoid{}, nil
+	}
+
+	v, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Return{Result: v}, nil
+}
+
+func (p *parser) parseConditionalBranch() (Instruction, error) {
+	p.next()
+
+	cond, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := p.parseBlockRef()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	alt, err := p.parseBlockRef()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConditionalBranch{Condition: cond, Action: action, Alternative: alt}, nil
+}
+
+func (p *parser) parseSwitch() (Instruction, error) {
+	p.next()
+
+	cond, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := p.parseBlockRef()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, "[")
+	if err != nil {
+		return nil, err
+	}
+
+	instr := &Switch{Value: cond, Done: done}
+	for !p.is(tPunct, "]") {
+		if len(instr.Blocks) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		v, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tPunct, ":")
+		if err != nil {
+			return nil, err
+		}
+
+		blk, err := p.parseBlockRef()
+		if err != nil {
+			return nil, err
+		}
+
+		instr.Blocks = append(instr.Blocks, &SwitchValueBlock{Value: v, Block: blk})
+	}
+
+	p.next()
+	return instr, nil
+}
+
+var binaryInstructions = map[string]func(l, r Value) Value{
+	"add":  func(l, r Value) Value { return &Add{Left: l, Right: r} },
+	"fadd": func(l, r Value) Value { return &FAdd{Left: l, Right: r} },
+	"sub":  func(l, r Value) Value { return &Sub{Left: l, Right: r} },
+	"fsub": func(l, r Value) Value { return &FSub{Left: l, Right: r} },
+	"mul":  func(l, r Value) Value { return &Mul{Left: l, Right: r} },
+	"fmul": func(l, r Value) Value { return &FMul{Left: l, Right: r} },
+	"udiv": func(l, r Value) Value { return &UDiv{Left: l, Right: r} },
+	"sdiv": func(l, r Value) Value { return &SDiv{Left: l, Right: r} },
+	"fdiv": func(l, r Value) Value { return &FDiv{Left: l, Right: r} },
+	"urem": func(l, r Value) Value { return &URem{Left: l, Right: r} },
+	"srem": func(l, r Value) Value { return &SRem{Left: l, Right: r} },
+	"frem": func(l, r Value) Value { return &FRem{Left: l, Right: r} },
+	"xor":  func(l, r Value) Value { return &XOR{Left: l, Right: r} },
+	"and":  func(l, r Value) Value { return &AND{Left: l, Right: r} },
+	"or":   func(l, r Value) Value { return &OR{Left: l, Right: r} },
+	"shl":  func(l, r Value) Value { return &ShiftLeft{Left: l, Right: r} },
+	"ashr": func(l, r Value) Value { return &ArithmeticShiftRight{Left: l, Right: r} },
+	"lshr": func(l, r Value) Value { return &LogicalShiftRight{Left: l, Right: r} },
+}
+
+func (p *parser) parseValueInstruction() (Value, error) {
+	tok, err := p.expect(tName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.lit {
+	case "alloca", "alloca.heap":
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Allocate{TypeOf: t, OnHeap: tok.lit == "alloca.heap"}, nil
+	case "load":
+		addr, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Load{Address: addr}, nil
+	case "call":
+		return p.parseCall()
+	case "call.ptr":
+		return p.parseIndirectCall()
+	case "bitcast":
+		v, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tName, "to")
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		return &BitCast{Value: v, To: t}, nil
+	case "phi":
+		return p.parsePHI()
+	case "field", "extract":
+		return p.parseMemberAccess(tok)
+	case "offset":
+		addr, offset, err := p.parseOperandPair()
+		if err != nil {
+			return nil, err
+		}
+
+		return &PointerOffset{Address: addr, Offset: offset}, nil
+	case "ineg", "fneg":
+		v, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok.lit == "ineg" {
+			return &INeg{Right: v}, nil
+		}
+		return &FNeg{Right: v}, nil
+	case "icmp", "fcmp":
+		return p.parseComparison(tok)
+	}
+
+	build, ok := binaryInstructions[tok.lit]
+	if !ok {
+		return nil, p.errorf(tok, "unknown instruction `%s`", tok.lit)
+	}
+
+	lhs, rhs, err := p.parseOperandPair()
+	if err != nil {
+		return nil, err
+	}
+
+	return build(lhs, rhs), nil
+}
+
+func (p *parser) parseOperandPair() (Value, Value, error) {
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rhs, err := p.parseOperand()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lhs, rhs, nil
+}
+
+func (p *parser) parseCall() (Value, error) {
+	tok, err := p.expect(tGlobal, "")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, "(")
+	if err != nil {
+		return nil, err
+	}
+
+	call := &Call{Target: p.function(tok)}
+	for !p.is(tPunct, ")") {
+		if len(call.Arguments) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		call.Arguments = append(call.Arguments, arg)
+	}
+
+	p.next()
+	return call, nil
+}
+
+func (p *parser) parseIndirectCall() (Value, error) {
+	callee, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, "(")
+	if err != nil {
+		return nil, err
+	}
+
+	call := &IndirectCall{Callee: callee}
+	for !p.is(tPunct, ")") {
+		if len(call.Arguments) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		call.Arguments = append(call.Arguments, arg)
+	}
+
+	p.next()
+	return call, nil
+}
+
+func (p *parser) parsePHI() (Value, error) {
+	phi := &PHI{}
+
+	for len(phi.Nodes) == 0 || p.is(tPunct, ",") {
+		if len(phi.Nodes) != 0 {
+			p.next()
+		}
+
+		_, err := p.expect(tPunct, "[")
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tPunct, ",")
+		if err != nil {
+			return nil, err
+		}
+
+		blk, err := p.parseBlockRef()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tPunct, "]")
+		if err != nil {
+			return nil, err
+		}
+
+		phi.Nodes = append(phi.Nodes, &PhiNode{Value: v, Block: blk})
+	}
+
+	return phi, nil
+}
+
+func (p *parser) parseMemberAccess(op lirToken) (Value, error) {
+	var composite *Composite
+
+	// Tuples, `field (int, bool), %0, 1`
+	if p.is(tPunct, "(") {
+		t, err := p.parseTupleType()
+		if err != nil {
+			return nil, err
+		}
+
+		composite = NewTupleComposite(t)
+	} else {
+		tok, err := p.expect(tLocal, "")
+		if err != nil {
+			return nil, err
+		}
+
+		composite = p.composite(tok)
+	}
+
+	_, err := p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := p.expect(tNumber, "")
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := strconv.Atoi(idx.lit)
+	if err != nil || index < 0 {
+		return nil, p.errorf(idx, "invalid member index %s", idx.lit)
+	}
+
+	if op.lit == "field" {
+		return &AccessStructProperty{Composite: composite, Address: addr, Index: index}, nil
+	}
+
+	return &ExtractValue{Composite: composite, Address: addr, Index: index}, nil
+}
+
+func (p *parser) parseComparison(op lirToken) (Value, error) {
+	tok, err := p.expect(tName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var comparison ICompOp
+	for k, v := range ICompOpNames {
+		if v == tok.lit {
+			comparison = k
+		}
+	}
+
+	if comparison == INVALID_ICOMP {
+		return nil, p.errorf(tok, "unknown comparison `%s`", tok.lit)
+	}
+
+	lhs, rhs, err := p.parseOperandPair()
+	if err != nil {
+		return nil, err
+	}
+
+	if op.lit == "icmp" {
+		return &ICmp{Left: lhs, Right: rhs, Comparison: comparison}, nil
+	}
+
+	return &FCmp{Left: lhs, Right: rhs, Comparison: comparison}, nil
+}
+
+// * Operands
+
+func (p *parser) parseOperand() (Value, error) {
+	tok := p.current()
+
+	switch tok.kind {
+	case tLocal:
+		p.next()
+		if v, ok := p.values[tok.lit]; ok {
+			return v, nil
+		}
+
+		fv, ok := p.forward[tok.lit]
+		if !ok {
+			fv = &forwardValue{tok: tok}
+			p.forward[tok.lit] = fv
+		}
+
+		return fv, nil
+	case tGlobal:
+		p.next()
+		if g, ok := p.globals[tok.lit]; ok {
+			return g, nil
+		}
+
+		return p.function(tok), nil
+	}
+
+	return p.parseConstant()
+}
+
+func (p *parser) parseConstant() (*Constant, error) {
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	if isVoid(t) {
+		return NewConst(nil, t), nil
+	}
+
+	tok := p.next()
+
+	switch {
+	case tok.quoted:
+		return NewConst(tok.lit, t), nil
+	case tok.kind == tName && tok.lit == "null":
+		return NewConst(nil, t), nil
+	case tok.kind == tName && (tok.lit == "true" || tok.lit == "false"):
+		return NewConst(tok.lit == "true", t), nil
+	case tok.kind == tNumber:
+		if types.IsFloatingPoint(t) || isFloatLiteral(t) {
+			v, err := strconv.ParseFloat(tok.lit, 64)
+			if err != nil {
+				return nil, p.errorf(tok, "invalid float constant %s", tok.lit)
+			}
+
+			return NewConst(v, t), nil
+		}
+
+		v, err := strconv.ParseInt(tok.lit, 10, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid integer constant %s", tok.lit)
+		}
+
+		return NewConst(v, t), nil
+	}
+
+	return nil, p.errorf(tok, "expected constant, found `%s`", tok)
+}
+
+func isFloatLiteral(t types.Type) bool {
+	b, ok := t.Parent().(*types.Basic)
+	return ok && b.Literal == types.FloatLiteral
+}
+
+// * Types
+
+// maps the printed names of basic types to their global definitions
+var basicTypes = func() map[string]types.Type {
+	m := make(map[string]types.Type)
+	for t := types.Unresolved; t <= types.NilLiteral; t++ {
+		typ := types.LookUp(t)
+		if b, ok := typ.Parent().(*types.Basic); ok && b.Literal == t {
+			m[basicName(b)] = typ
+		}
+	}
+
+	return m
+}()
+
+func (p *parser) parseType() (types.Type, error) {
+	tok := p.current()
+
+	switch {
+	case tok.kind == tLocal:
+		p.next()
+		return p.composite(tok).Type, nil
+	case p.is(tPunct, "*"):
+		p.next()
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		return types.NewPointer(t), nil
+	case p.is(tPunct, "["):
+		return p.parseArrayType()
+	case p.is(tPunct, "("):
+		return p.parseTupleType()
+	case p.is(tName, "fn"):
+		return p.parseSignatureType()
+	case p.is(tName, "fnptr"):
+		sg, err := p.parseSignatureType()
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionPointer{Signature: sg.(*types.FunctionSignature)}, nil
+	case tok.kind == tName:
+		if t, ok := basicTypes[tok.lit]; ok {
+			p.next()
+			return t, nil
+		}
+	}
+
+	return nil, p.errorf(tok, "expected type, found `%s`", tok)
+}
+
+func (p *parser) parseArrayType() (types.Type, error) {
+	p.next()
+
+	count, err := p.expect(tNumber, "")
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(count.lit)
+	if err != nil || n < 0 {
+		return nil, p.errorf(count, "invalid array length %s", count.lit)
+	}
+
+	_, err = p.expect(tName, "x")
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tPunct, "]")
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticArray{OfType: t, Count: n}, nil
+}
+
+func (p *parser) parseTupleType() (*types.Tuple, error) {
+	p.next()
+
+	elements := []types.Type{}
+	for !p.is(tPunct, ")") {
+		if len(elements) != 0 {
+			_, err := p.expect(tPunct, ",")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, t)
+	}
+
+	p.next()
+	return types.NewTuple(elements), nil
+}
+
+func (p *parser) parseSignatureType() (types.Type, error) {
+	p.next()
+
+	_, err := p.expect(tPunct, "(")
+	if err != nil {
+		return nil, err
+	}
+
+	sg := types.NewFunctionSignature()
+	for !p.is(tPunct, ")") {
+		if len(sg.Parameters) != 0 {
+			_, err = p.expect(tPunct, ",")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		sg.AddParameter(types.NewVar("", t, p.mod.TModule))
+	}
+
+	p.next()
+
+	_, err = p.expect(tPunct, "->")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	sg.Result.SetType(result)
+	return sg, nil
+}
@@ -0,0 +1,286 @@
+package lir
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `module app::main
+
+composite %app::main::Pair = { int, *%app::main::Pair }
+
+global @app::main::limit = int 10
+
+fn @app::main::count(int %0) -> int {
+b0:
+	br b1
+b1:
+	%1 = phi [int 0, b0], [%3, b2]
+	%2 = icmp slt %1, @app::main::limit
+	condbr %2, b2, b3
+b2:
+	%3 = add %1, int 1
+	br b1
+b3:
+	ret %1
+}
+
+fn @app::main::main() -> void {
+b0:
+	%0 = alloca %app::main::Pair
+	%1 = field %app::main::Pair, %0, 0
+	store int 4, %1
+	%2 = field %app::main::Pair, %0, 1
+	store *%app::main::Pair null, %2
+	%3 = load %1
+	%4 = call @app::main::count(%3)
+	switch %4, b3 [int 0: b1, int 10: b2]
+b1:
+	call @exit(int 1)
+	ret void
+b2:
+	call @exit(int 0)
+	ret void
+b3:
+	ret void
+}
+
+extern fn @exit(int %0) -> void
+`
+
+func TestParseRoundTrip(t *testing.T) {
+	exec, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	printed := PrintExecutable(exec)
+	if printed != sample {
+		t.Fatalf("printed output does not match input\n\nexpected:\n%s\nfound:\n%s", sample, printed)
+	}
+
+	// parsing the printed output yields the same program
+	again, err := Parse(printed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reprinted := PrintExecutable(again); reprinted != printed {
+		t.Fatalf("reparsed output does not match\n\nexpected:\n%s\nfound:\n%s", printed, reprinted)
+	}
+}
+
+func TestParseNamedValues(t *testing.T) {
+	input := `module app::main
+
+fn @app::main::max(int %a, int %b) -> int {
+entry:
+	%gt = icmp sgt %a, %b
+	condbr %gt, left, right
+left:
+	ret %a
+right:
+	ret %b
+}
+`
+
+	expected := `module app::main
+
+fn @app::main::max(int %0, int %1) -> int {
+b0:
+	%2 = icmp sgt %0, %1
+	condbr %2, b1, b2
+b1:
+	ret %0
+b2:
+	ret %1
+}
+`
+
+	exec, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if printed := PrintExecutable(exec); printed != expected {
+		t.Fatalf("expected:\n%s\nfound:\n%s", expected, printed)
+	}
+}
+
+func TestParseQuotedNames(t *testing.T) {
+	input := `module std::std
+
+composite %"std::std::Box::_G::literal int" = { int }
+
+fn @"std::std::unbox::_G::literal int"(*%"std::std::Box::_G::literal int" %0) -> int {
+b0:
+	%1 = field %"std::std::Box::_G::literal int", %0, 0
+	%2 = load %1
+	ret %2
+}
+`
+
+	exec, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if printed := PrintExecutable(exec); printed != input {
+		t.Fatalf("expected:\n%s\nfound:\n%s", input, printed)
+	}
+}
+
+func TestParseClosures(t *testing.T) {
+	input := `module app::main
+
+composite %app::main::adder::closure1::env = { fnptr(int) -> int, int }
+
+fn @app::main::adder(int %0) -> fn(int) -> int {
+b0:
+	%1 = alloca.heap %app::main::adder::closure1::env
+	%2 = field %app::main::adder::closure1::env, %1, 0
+	store @app::main::adder::closure1, %2
+	%3 = field %app::main::adder::closure1::env, %1, 1
+	store %0, %3
+	%4 = bitcast %1 to fn(int) -> int
+	ret %4
+}
+
+fn @app::main::adder::closure1(fn(int) -> int %0, int %1) -> int {
+b0:
+	%2 = bitcast %0 to *%app::main::adder::closure1::env
+	%3 = field %app::main::adder::closure1::env, %2, 1
+	%4 = load %3
+	%5 = add %1, %4
+	ret %5
+}
+
+fn @app::main::apply(fn(int) -> int %0, int %1) -> int {
+b0:
+	%2 = bitcast %0 to *fnptr(int) -> int
+	%3 = load %2
+	%4 = call.ptr %3(%0, %1)
+	ret %4
+}
+`
+
+	exec, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if printed := PrintExecutable(exec); printed != input {
+		t.Fatalf("expected:\n%s\nfound:\n%s", input, printed)
+	}
+
+	if err := Validate(exec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseTuples(t *testing.T) {
+	input := `module app::main
+
+fn @app::main::divide(int %0, int %1) -> (int, bool) {
+b0:
+	%2 = alloca.heap (int, bool)
+	%3 = field (int, bool), %2, 0
+	%4 = sdiv %0, %1
+	store %4, %3
+	%5 = field (int, bool), %2, 1
+	store bool true, %5
+	ret %2
+}
+
+fn @app::main::main() -> int {
+b0:
+	%0 = call @app::main::divide(int 84, int 2)
+	%1 = field (int, bool), %0, 0
+	%2 = load %1
+	ret %2
+}
+`
+
+	exec, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if printed := PrintExecutable(exec); printed != input {
+		t.Fatalf("expected:\n%s\nfound:\n%s", input, printed)
+	}
+
+	if err := Validate(exec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	input := `module app::main
+
+fn @app::main::greet() -> string {
+b0:
+	%0 = call @strlen(string "null")
+	%1 = call @puts(string "say \"hi\"\n")
+	ret string "null"
+}
+
+extern fn @puts(string %0) -> int
+
+extern fn @strlen(string %0) -> u64
+`
+
+	exec, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if printed := PrintExecutable(exec); printed != input {
+		t.Fatalf("expected:\n%s\nfound:\n%s", input, printed)
+	}
+
+	// quoted constants are strings, even when they read as a keyword
+	var fn *Function
+	for _, m := range exec.Modules {
+		fn = m.Functions["app::main::greet"]
+	}
+
+	ret := fn.Blocks[0].Instructions[2].(*Return)
+	if v, ok := ret.Result.(*Constant).Value.(string); !ok || v != "null" {
+		t.Errorf("expected the string constant \"null\", found %v", ret.Result)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"fn @f() -> void {\nb0:\n\tret void\n}", "expected `module`"},
+		{"module main", "expected module name"},
+		{"module app::main\nfn @f() -> void {\n\tret void\n}", "expected block label"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\tbr b1\n}", "undefined block b1"},
+		{"module app::main\nfn @f() -> int {\nb0:\n\tret %1\n}", "undefined value %1"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\tcall @g()\n\tret void\n}", "undefined @g"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\t%0 = alloca %Missing\n\tret void\n}", "undefined %Missing"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\t%0 = call @f()\n\tret void\n}", "yields void"},
+		{"module app::main\nfn @f() -> int {\nb0:\n\tcall @f()\n\tret int 0\n}", "must be assigned"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\tret void\n\tret void\n}", "instruction after return"},
+		{"module app::main\nfn @f(int %0, int %0) -> void {\nb0:\n\tret void\n}", "redefinition of value %0"},
+		{"module app::main\nfn @f() -> void {\nb0:\n\t%0 = nop\n\tret void\n}", "unknown instruction `nop`"},
+		{"module app::main\nfn @f() -> money {\nb0:\n\tret void\n}", "expected type"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.input)
+
+		if err == nil {
+			t.Errorf("expected error containing %q, parsed successfully\n%s", test.err, test.input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, err)
+		}
+	}
+}
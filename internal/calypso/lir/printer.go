@@ -0,0 +1,560 @@
+package lir
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+/*
+Renders LIR in a textual form
+
+	composite %app::main::Point = { int, int }
+
+	fn @app::main::add(int %0, int %1) -> int {
+	b0:
+		%2 = add %0, %1
+		ret %2
+	}
+
+Values are numbered in order of definition, starting with the parameters of a function, blocks are named after their index.
+*/
+type printer struct {
+	b          strings.Builder
+	composites map[types.Type]*Composite
+	globals    map[*Global]string
+	ids        map[Value]int
+	printed    map[Value]bool
+	next       int
+}
+
+func newPrinter() *printer {
+	return &printer{
+		composites: make(map[types.Type]*Composite),
+		globals:    make(map[*Global]string),
+	}
+}
+
+func PrintExecutable(e *Executable) string {
+	p := newPrinter()
+	for t, c := range e.Composites {
+		p.composites[t] = c
+	}
+
+	modules := sortedModules(e)
+	for _, m := range modules {
+		p.collect(m)
+	}
+
+	for i, m := range modules {
+		if i != 0 {
+			p.b.WriteString("\n")
+		}
+		p.module(m)
+	}
+
+	return p.b.String()
+}
+
+func PrintModule(m *Module) string {
+	p := newPrinter()
+	p.collect(m)
+	p.module(m)
+	return p.b.String()
+}
+
+func PrintFunction(fn *Function) string {
+	p := newPrinter()
+	p.function(fn)
+	return p.b.String()
+}
+
+func sortedModules(e *Executable) []*Module {
+	modules := []*Module{}
+	for _, m := range e.Modules {
+		modules = append(modules, m)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].ID() < modules[j].ID()
+	})
+
+	return modules
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// collects the named members of a module so they can be referenced by name
+func (p *printer) collect(m *Module) {
+	for _, c := range m.Composites {
+		p.composites[c.Type] = c
+	}
+
+	for _, g := range m.GTypes {
+		for _, c := range g.Specs {
+			p.composites[c.Type] = c
+		}
+	}
+
+	for k, g := range m.GlobalConstants {
+		p.globals[g] = k
+	}
+}
+
+func (p *printer) module(m *Module) {
+	fmt.Fprintf(&p.b, "module %s\n", quoteName(m.TModule.SymbolName()))
+
+	composites := []*Composite{}
+	for _, k := range sortedKeys(m.Composites) {
+		composites = append(composites, m.Composites[k])
+	}
+
+	for _, k := range sortedKeys(m.GTypes) {
+		g := m.GTypes[k]
+		for _, s := range sortedKeys(g.Specs) {
+			composites = append(composites, g.Specs[s])
+		}
+	}
+
+	if len(composites) != 0 {
+		p.b.WriteString("\n")
+	}
+
+	for _, c := range composites {
+		p.composite(c)
+	}
+
+	if len(m.GlobalConstants) != 0 {
+		p.b.WriteString("\n")
+	}
+
+	for _, k := range sortedKeys(m.GlobalConstants) {
+		fmt.Fprintf(&p.b, "global @%s = %s\n", quoteName(k), p.constant(m.GlobalConstants[k].Value))
+	}
+
+	for _, k := range sortedKeys(m.Functions) {
+		p.b.WriteString("\n")
+		p.function(m.Functions[k])
+	}
+}
+
+func (p *printer) composite(c *Composite) {
+	members := []string{}
+	for _, m := range c.Members {
+		members = append(members, p.typ(m))
+	}
+
+	fmt.Fprintf(&p.b, "composite %%%s = { %s }\n", quoteName(c.Name), strings.Join(members, ", "))
+}
+
+func (p *printer) function(fn *Function) {
+	p.ids = make(map[Value]int)
+	p.printed = make(map[Value]bool)
+	p.next = 0
+
+	params := []string{}
+	for _, param := range fn.Parameters {
+		params = append(params, fmt.Sprintf("%s %s", p.typ(param.Symbol), p.define(param)))
+	}
+
+	// number emitted values up front so operands defined in later blocks, such as phi incoming values, can be referenced
+	for _, blk := range fn.Blocks {
+		for _, i := range blk.Instructions {
+			if v, ok := i.(Value); ok && IsInstruction(v) && !isVoidCall(v) {
+				p.define(v)
+			}
+		}
+	}
+
+	header := fmt.Sprintf("fn @%s(%s) -> %s", quoteName(fn.Name), strings.Join(params, ", "), p.typ(fn.Signature().Result.Type()))
+
+	if fn.External {
+		fmt.Fprintf(&p.b, "extern %s\n", header)
+		return
+	}
+
+	fmt.Fprintf(&p.b, "%s {\n", header)
+
+	for _, blk := range fn.Blocks {
+		fmt.Fprintf(&p.b, "%s:\n", blockName(blk))
+		for _, i := range blk.Instructions {
+			p.instruction(i)
+		}
+	}
+
+	p.b.WriteString("}\n")
+}
+
+func blockName(b *Block) string {
+	return "b" + strconv.Itoa(b.Index)
+}
+
+func (p *printer) define(v Value) string {
+	id := p.next
+	p.ids[v] = id
+	p.next++
+	return "%" + strconv.Itoa(id)
+}
+
+func (p *printer) line(format string, args ...any) {
+	p.b.WriteString("\t")
+	fmt.Fprintf(&p.b, format, args...)
+	p.b.WriteString("\n")
+}
+
+func (p *printer) instruction(i Instruction) {
+	switch i := i.(type) {
+	case *Store:
+		val, addr := p.operand(i.Value), p.operand(i.Address)
+		p.line("store %s, %s", val, addr)
+	case *Return:
+		p.line("ret %s", p.operand(i.Result))
+	case *ReturnVoid:
+		p.line("ret void")
+	case *Branch:
+		p.line("br %s", blockName(i.Block))
+	case *ConditionalBranch:
+		p.line("condbr %s, %s, %s", p.operand(i.Condition), blockName(i.Action), blockName(i.Alternative))
+	case *Switch:
+		cond := p.operand(i.Value)
+		cases := []string{}
+		for _, c := range i.Blocks {
+			cases = append(cases, fmt.Sprintf("%s: %s", p.operand(c.Value), blockName(c.Block)))
+		}
+		p.line("switch %s, %s [%s]", cond, blockName(i.Done), strings.Join(cases, ", "))
+	case Value:
+		if IsInstruction(i) {
+			p.value(i)
+		}
+	default:
+		p.line("; unknown instruction %T", i)
+	}
+}
+
+// Reports whether a value is computed by an instruction, as opposed to constants, parameters & named members
+func IsInstruction(v Value) bool {
+	switch v.(type) {
+	case *Load, *Allocate, *Call, *IndirectCall, *PHI, *AccessStructProperty, *PointerOffset, *ExtractValue, *BitCast,
+		*Add, *FAdd, *Sub, *FSub, *Mul, *FMul, *UDiv, *SDiv, *FDiv, *URem, *SRem, *FRem,
+		*INeg, *FNeg, *ICmp, *FCmp, *XOR, *ShiftLeft, *ArithmeticShiftRight, *LogicalShiftRight, *AND, *OR:
+		return true
+	}
+
+	return false
+}
+
+// prints the definition of an instruction value, instructions are defined once at the point they are first emitted or used
+func (p *printer) value(v Value) {
+	if p.printed[v] {
+		return
+	}
+
+	p.printed[v] = true
+
+	var body string
+	switch v := v.(type) {
+	case *Allocate:
+		op := "alloca"
+		if v.OnHeap {
+			op = "alloca.heap"
+		}
+		body = fmt.Sprintf("%s %s", op, p.typ(v.TypeOf))
+	case *Load:
+		body = fmt.Sprintf("load %s", p.operand(v.Address))
+	case *Call:
+		args := []string{}
+		for _, a := range v.Arguments {
+			args = append(args, p.operand(a))
+		}
+		body = fmt.Sprintf("call @%s(%s)", quoteName(v.Target.Name), strings.Join(args, ", "))
+
+		// calls yielding void do not define a value
+		if isVoid(v.Yields()) {
+			p.line("%s", body)
+			return
+		}
+	case *IndirectCall:
+		args := []string{}
+		for _, a := range v.Arguments {
+			args = append(args, p.operand(a))
+		}
+		body = fmt.Sprintf("call.ptr %s(%s)", p.operand(v.Callee), strings.Join(args, ", "))
+
+		if isVoid(v.Yields()) {
+			p.line("%s", body)
+			return
+		}
+	case *PHI:
+		nodes := []string{}
+		for _, n := range v.Nodes {
+			nodes = append(nodes, fmt.Sprintf("[%s, %s]", p.operand(n.Value), blockName(n.Block)))
+		}
+		body = fmt.Sprintf("phi %s", strings.Join(nodes, ", "))
+	case *AccessStructProperty:
+		body = fmt.Sprintf("field %s, %s, %d", p.compositeName(v.Composite), p.operand(v.Address), v.Index)
+	case *ExtractValue:
+		body = fmt.Sprintf("extract %s, %s, %d", p.compositeName(v.Composite), p.operand(v.Address), v.Index)
+	case *PointerOffset:
+		addr, offset := p.operand(v.Address), p.operand(v.Offset)
+		body = fmt.Sprintf("offset %s, %s", addr, offset)
+	case *BitCast:
+		body = fmt.Sprintf("bitcast %s to %s", p.operand(v.Value), p.typ(v.To))
+	case *INeg:
+		body = fmt.Sprintf("ineg %s", p.operand(v.Right))
+	case *FNeg:
+		body = fmt.Sprintf("fneg %s", p.operand(v.Right))
+	case *ICmp:
+		lhs, rhs := p.operand(v.Left), p.operand(v.Right)
+		body = fmt.Sprintf("icmp %s %s, %s", ICompOpNames[v.Comparison], lhs, rhs)
+	case *FCmp:
+		lhs, rhs := p.operand(v.Left), p.operand(v.Right)
+		body = fmt.Sprintf("fcmp %s %s, %s", ICompOpNames[v.Comparison], lhs, rhs)
+	default:
+		op, lhs, rhs := binaryOperands(v)
+		body = fmt.Sprintf("%s %s, %s", op, p.operand(lhs), p.operand(rhs))
+	}
+
+	if _, ok := p.ids[v]; !ok {
+		p.define(v)
+	}
+
+	p.line("%%%d = %s", p.ids[v], body)
+}
+
+func isVoidCall(v Value) bool {
+	switch c := v.(type) {
+	case *Call:
+		return isVoid(c.Yields())
+	case *IndirectCall:
+		return isVoid(c.Yields())
+	}
+
+	return false
+}
+
+func binaryOperands(v Value) (string, Value, Value) {
+	switch v := v.(type) {
+	case *Add:
+		return "add", v.Left, v.Right
+	case *FAdd:
+		return "fadd", v.Left, v.Right
+	case *Sub:
+		return "sub", v.Left, v.Right
+	case *FSub:
+		return "fsub", v.Left, v.Right
+	case *Mul:
+		return "mul", v.Left, v.Right
+	case *FMul:
+		return "fmul", v.Left, v.Right
+	case *UDiv:
+		return "udiv", v.Left, v.Right
+	case *SDiv:
+		return "sdiv", v.Left, v.Right
+	case *FDiv:
+		return "fdiv", v.Left, v.Right
+	case *URem:
+		return "urem", v.Left, v.Right
+	case *SRem:
+		return "srem", v.Left, v.Right
+	case *FRem:
+		return "frem", v.Left, v.Right
+	case *XOR:
+		return "xor", v.Left, v.Right
+	case *AND:
+		return "and", v.Left, v.Right
+	case *OR:
+		return "or", v.Left, v.Right
+	case *ShiftLeft:
+		return "shl", v.Left, v.Right
+	case *ArithmeticShiftRight:
+		return "ashr", v.Left, v.Right
+	case *LogicalShiftRight:
+		return "lshr", v.Left, v.Right
+	}
+
+	panic(fmt.Sprintf("unknown binary instruction, %T", v))
+}
+
+var ICompOpNames = map[ICompOp]string{
+	EQL:  "eq",
+	NEQ:  "ne",
+	ULSS: "ult",
+	UGTR: "ugt",
+	UGEQ: "uge",
+	ULEQ: "ule",
+	SLSS: "slt",
+	SGTR: "sgt",
+	SGEQ: "sge",
+	SLEQ: "sle",
+}
+
+func (p *printer) operand(v Value) string {
+	switch v := v.(type) {
+	case *Constant:
+		return p.constant(v)
+	case *Global:
+		if k, ok := p.globals[v]; ok {
+			return "@" + quoteName(k)
+		}
+		return p.constant(v.Value)
+	case *Function:
+		return "@" + quoteName(v.Name)
+	}
+
+	id, ok := p.ids[v]
+
+	// instructions used without being emitted are materialized at their first use, as the backend does
+	if !ok && IsInstruction(v) {
+		p.value(v)
+		id, ok = p.ids[v]
+	}
+
+	if !ok {
+		return fmt.Sprintf("<%T>", v)
+	}
+
+	return "%" + strconv.Itoa(id)
+}
+
+func (p *printer) constant(c *Constant) string {
+	t := p.typ(c.Yields())
+	switch v := c.Value.(type) {
+	case nil:
+		if isVoid(c.Yields()) {
+			return "void"
+		}
+		return t + " null"
+	case float64:
+		return t + " " + strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return t + " " + strconv.Quote(v)
+	default:
+		if _, ok := c.Yields().Parent().(*types.Pointer); ok {
+			return t + " null"
+		}
+
+		if isVoid(c.Yields()) {
+			return "void"
+		}
+		return fmt.Sprintf("%s %v", t, v)
+	}
+}
+
+func isVoid(t types.Type) bool {
+	b, ok := t.Parent().(*types.Basic)
+	return ok && b.Literal == types.Void
+}
+
+var basicNames = map[types.BasicType]string{
+	types.Unresolved:     "unresolved",
+	types.Placeholder:    "placeholder",
+	types.IntegerLiteral: "lit.int",
+	types.FloatLiteral:   "lit.float",
+	types.NilLiteral:     "lit.nil",
+}
+
+func basicName(t *types.Basic) string {
+	if n, ok := basicNames[t.Literal]; ok {
+		return n
+	}
+
+	return t.Name()
+}
+
+// names which are not made up of identifier characters, such as specializations, are quoted
+func quoteName(s string) string {
+	for i, r := range s {
+		if !isNameRune(r, i == 0) {
+			return strconv.Quote(s)
+		}
+	}
+
+	// a single colon ends a name
+	if s == "" || strings.Contains(strings.ReplaceAll(s, "::", ""), ":") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+func isNameRune(r rune, first bool) bool {
+	switch {
+	case r == '_' || r == '.':
+		return true
+	case r == ':':
+		return !first
+	case unicode.IsLetter(r):
+		return true
+	case unicode.IsDigit(r):
+		return !first
+	}
+
+	return false
+}
+
+func (p *printer) signature(sg *types.FunctionSignature) string {
+	params := []string{}
+	for _, param := range sg.Parameters {
+		params = append(params, p.typ(param.Type()))
+	}
+	return fmt.Sprintf("(%s) -> %s", strings.Join(params, ", "), p.typ(sg.Result.Type()))
+}
+
+// anonymous composites are named by their type
+func (p *printer) compositeName(c *Composite) string {
+	if c.Name == "" {
+		return p.typ(c.Type)
+	}
+
+	return "%" + quoteName(c.Name)
+}
+
+func (p *printer) typ(t types.Type) string {
+	if t == nil {
+		return "void"
+	}
+
+	if t, ok := t.(*types.Tuple); ok {
+		elements := []string{}
+		for _, e := range t.Elements {
+			elements = append(elements, p.typ(e))
+		}
+		return "(" + strings.Join(elements, ", ") + ")"
+	}
+
+	if c, ok := p.composites[t]; ok {
+		return "%" + quoteName(c.Name)
+	}
+
+	switch x := t.Parent().(type) {
+	case *types.Basic:
+		return basicName(x)
+	case *types.Pointer:
+		return "*" + p.typ(x.PointerTo)
+	case *StaticArray:
+		return fmt.Sprintf("[%d x %s]", x.Count, p.typ(x.OfType))
+	case *types.FunctionSignature:
+		return "fn" + p.signature(x)
+	case *FunctionPointer:
+		return "fnptr" + p.signature(x.Signature)
+	case *types.Struct, *types.Enum:
+		switch t.(type) {
+		case *types.DefinedType, *types.SpecializedType:
+			return "%" + quoteName(types.SymbolName(t))
+		}
+	}
+
+	return strings.ReplaceAll(t.String(), " ", "_")
+}
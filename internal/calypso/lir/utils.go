@@ -9,7 +9,7 @@ func SizeOf(t types.Type) uint64 {
 	switch t := t.Parent().(type) {
 	case *types.Basic:
 		return sizeOfBasic(t)
-	case *types.Pointer:
+	case *types.Pointer, *types.FunctionSignature, *FunctionPointer:
 		return 8
 	case *types.Struct:
 		return sizeOfStruct(t)
@@ -1 +1,340 @@
 package lir
+
+import (
+	"fmt"
+
+	"github.com/mantton/calypso/internal/calypso/lexer"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+/*
+Verifies the structure of generated LIR
+
+  - every block ends in exactly one terminator
+  - operands of arithmetic, comparison & store instructions agree in type
+  - phi nodes only name predecessors of their block & their incoming values agree in type
+  - calls provide an argument for each parameter of their target
+  - indirect calls go through a function pointer & bitcasts convert between pointers
+
+Problems are reported per function, so lirgen bugs surface as diagnostics rather than LLVM verifier failures or panics in the backend.
+*/
+func Validate(e *Executable) error {
+	errs := []error{}
+
+	for _, m := range sortedModules(e) {
+		for _, k := range sortedKeys(m.Functions) {
+			errs = append(errs, ValidateFunction(m.Functions[k])...)
+		}
+	}
+
+	if len(errs) != 0 {
+		return lexer.CombinedErrors(errs)
+	}
+
+	return nil
+}
+
+type validator struct {
+	fn    *Function
+	preds map[*Block]map[*Block]bool
+	errs  []error
+}
+
+func ValidateFunction(fn *Function) []error {
+	if fn.External {
+		return nil
+	}
+
+	v := &validator{
+		fn:    fn,
+		preds: make(map[*Block]map[*Block]bool),
+	}
+
+	if len(fn.Blocks) == 0 {
+		v.errorf(nil, "function has no blocks")
+		return v.errs
+	}
+
+	for _, blk := range fn.Blocks {
+		for _, succ := range Successors(blk) {
+			if v.preds[succ] == nil {
+				v.preds[succ] = make(map[*Block]bool)
+			}
+
+			v.preds[succ][blk] = true
+		}
+	}
+
+	for _, blk := range fn.Blocks {
+		v.block(blk)
+	}
+
+	return v.errs
+}
+
+func (v *validator) errorf(blk *Block, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	if blk != nil {
+		msg = fmt.Sprintf("%s: %s", blockName(blk), msg)
+	}
+
+	v.errs = append(v.errs, fmt.Errorf("\ninvalid lir in @%s, %s", v.fn.Name, msg))
+}
+
+// Reports whether an instruction ends a block
+func IsTerminator(i Instruction) bool {
+	switch i.(type) {
+	case *Return, *ReturnVoid, *Branch, *ConditionalBranch, *Switch:
+		return true
+	}
+
+	return false
+}
+
+// Returns the blocks control may transfer to from the terminator of a block
+func Successors(blk *Block) []*Block {
+	if len(blk.Instructions) == 0 {
+		return nil
+	}
+
+	switch i := blk.Instructions[len(blk.Instructions)-1].(type) {
+	case *Branch:
+		return []*Block{i.Block}
+	case *ConditionalBranch:
+		return []*Block{i.Action, i.Alternative}
+	case *Switch:
+		blocks := []*Block{i.Done}
+		for _, c := range i.Blocks {
+			blocks = append(blocks, c.Block)
+		}
+		return blocks
+	}
+
+	return nil
+}
+
+func (v *validator) block(blk *Block) {
+	if len(blk.Instructions) == 0 {
+		v.errorf(blk, "block is empty, expected a terminator")
+		return
+	}
+
+	last := len(blk.Instructions) - 1
+	for idx, i := range blk.Instructions {
+		if IsTerminator(i) && idx != last {
+			v.errorf(blk, "%s is followed by %d instruction(s), terminators must end a block", describe(i), last-idx)
+		}
+
+		v.instruction(blk, i)
+	}
+
+	if !IsTerminator(blk.Instructions[last]) {
+		v.errorf(blk, "block does not end in a terminator, found %s", describe(blk.Instructions[last]))
+	}
+}
+
+func (v *validator) instruction(blk *Block, i Instruction) {
+	for _, op := range Operands(i) {
+		if *op == nil {
+			v.errorf(blk, "%s has a nil operand", describe(i))
+			return
+		}
+	}
+
+	switch i := i.(type) {
+	case *Store:
+		ptr, ok := i.Address.Yields().(*types.Pointer)
+		if !ok {
+			v.errorf(blk, "store to non pointer address of type %s", i.Address.Yields())
+			return
+		}
+
+		// functions are stored in the environment of a closure, taking the environment ahead of their parameters
+		if fn, ok := i.Value.(*Function); ok {
+			if fp, ok := ptr.PointerTo.Parent().(*FunctionPointer); ok {
+				if !closureAgrees(fn, fp.Signature) {
+					v.errorf(blk, "store of @%s to address of type %s", fn.Name, ptr)
+				}
+				return
+			}
+		}
+
+		if !Agree(ptr.PointerTo, i.Value.Yields()) {
+			v.errorf(blk, "store of %s to address of type %s", i.Value.Yields(), ptr)
+		}
+	case *Add:
+		v.binary(blk, i, i.Left, i.Right)
+	case *ICmp:
+		v.binary(blk, i, i.Left, i.Right)
+	case *PHI:
+		if len(i.Nodes) == 0 {
+			v.errorf(blk, "phi has no incoming values")
+			return
+		}
+
+		for _, n := range i.Nodes {
+			if !v.preds[blk][n.Block] {
+				v.errorf(blk, "phi names %s, which is not a predecessor", blockName(n.Block))
+			}
+
+			if !Agree(i.Yields(), n.Value.Yields()) {
+				v.errorf(blk, "phi values disagree, %s & %s from %s", i.Yields(), n.Value.Yields(), blockName(n.Block))
+			}
+		}
+	case *Call:
+		if i.Target == nil {
+			v.errorf(blk, "call has no target")
+			return
+		}
+
+		if len(i.Arguments) != len(i.Target.Parameters) {
+			v.errorf(blk, "call to @%s with %d argument(s), expected %d", i.Target.Name, len(i.Arguments), len(i.Target.Parameters))
+		}
+	case *IndirectCall:
+		ptr, ok := i.Callee.Yields().(*FunctionPointer)
+		if !ok {
+			v.errorf(blk, "call.ptr through non function pointer of type %s", i.Callee.Yields())
+			return
+		}
+
+		// the environment of the closure precedes the arguments of the signature
+		if len(i.Arguments) != len(ptr.Signature.Parameters)+1 {
+			v.errorf(blk, "call.ptr with %d argument(s), expected %d", len(i.Arguments), len(ptr.Signature.Parameters)+1)
+		}
+	case *BitCast:
+		if !isPointerLike(i.Value.Yields()) || !isPointerLike(i.To) {
+			v.errorf(blk, "bitcast of %s to %s, expected pointers", i.Value.Yields(), i.To)
+		}
+	case *ConditionalBranch:
+		if i.Action == nil || i.Alternative == nil {
+			v.errorf(blk, "conditional branch is missing a destination")
+		}
+	case *Branch:
+		if i.Block == nil {
+			v.errorf(blk, "branch is missing a destination")
+		}
+	}
+}
+
+func (v *validator) binary(blk *Block, i Instruction, lhs, rhs Value) {
+	if !Agree(lhs.Yields(), rhs.Yields()) {
+		v.errorf(blk, "%s operands disagree, %s & %s", describe(i), lhs.Yields(), rhs.Yields())
+	}
+}
+
+func describe(i Instruction) string {
+	switch i.(type) {
+	case *Return, *ReturnVoid:
+		return "ret"
+	case *Branch:
+		return "br"
+	case *ConditionalBranch:
+		return "condbr"
+	case *Switch:
+		return "switch"
+	case *Store:
+		return "store"
+	case *Add:
+		return "add"
+	case *ICmp:
+		return "icmp"
+	case *PHI:
+		return "phi"
+	case *Call:
+		return "call"
+	case *IndirectCall:
+		return "call.ptr"
+	case *BitCast:
+		return "bitcast"
+	}
+
+	return fmt.Sprintf("%T", i)
+}
+
+// Reports whether two types have the same representation, integer literals agree with any integer type of their width
+func Agree(a, b types.Type) bool {
+	if a == b {
+		return true
+	}
+
+	switch x := a.Parent().(type) {
+	case *types.Basic:
+		y, ok := b.Parent().(*types.Basic)
+		if !ok {
+			return false
+		}
+
+		if x.Literal == y.Literal {
+			return true
+		}
+
+		if types.IsInteger(x) && types.IsInteger(y) {
+			return integerWidth(x) == integerWidth(y)
+		}
+
+		return false
+	case *types.Pointer:
+		y, ok := b.Parent().(*types.Pointer)
+		return ok && Agree(x.PointerTo, y.PointerTo)
+	case *StaticArray:
+		y, ok := b.Parent().(*StaticArray)
+		return ok && x.Count == y.Count && Agree(x.OfType, y.OfType)
+	case *types.FunctionSignature:
+		y, ok := b.Parent().(*types.FunctionSignature)
+		return ok && signaturesAgree(x, y)
+	case *FunctionPointer:
+		y, ok := b.Parent().(*FunctionPointer)
+		return ok && signaturesAgree(x.Signature, y.Signature)
+	}
+
+	return a.String() == b.String()
+}
+
+// signatures agree regardless of their parameter labels
+func signaturesAgree(a, b *types.FunctionSignature) bool {
+	if len(a.Parameters) != len(b.Parameters) {
+		return false
+	}
+
+	for i, p := range a.Parameters {
+		if !Agree(p.Type(), b.Parameters[i].Type()) {
+			return false
+		}
+	}
+
+	return Agree(a.Result.Type(), b.Result.Type())
+}
+
+// reports whether a function may be called through a pointer to a closure of a signature, the environment preceding its parameters
+func closureAgrees(fn *Function, sg *types.FunctionSignature) bool {
+	if len(fn.Parameters) != len(sg.Parameters)+1 {
+		return false
+	}
+
+	for i, p := range sg.Parameters {
+		if !Agree(fn.Parameters[i+1].Symbol, p.Type()) {
+			return false
+		}
+	}
+
+	return Agree(fn.Signature().Result.Type(), sg.Result.Type())
+}
+
+// closures are pointers to their environment
+func isPointerLike(t types.Type) bool {
+	switch t.Parent().(type) {
+	case *types.Pointer, *types.FunctionSignature:
+		return true
+	}
+
+	return false
+}
+
+func integerWidth(t *types.Basic) uint64 {
+	if t.Literal == types.IntegerLiteral {
+		return sizeOfBasic(types.LookUp(types.Int).Parent().(*types.Basic))
+	}
+
+	return sizeOfBasic(t)
+}
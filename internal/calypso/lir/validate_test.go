@@ -0,0 +1,66 @@
+package lir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSample(t *testing.T) {
+	exec, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Validate(exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		body string
+		err  string
+	}{
+		{"b0:\n\t%0 = alloca int", "does not end in a terminator"},
+		{"b0:\n\tbr b1\n\t%0 = alloca int\nb1:\n\tret void", "br is followed by 1 instruction(s)"},
+		{"b0:\n\tbr b1\n\tbr b1\nb1:\n\tret void", "br is followed by 1 instruction(s)"},
+		{"b0:\n\t%0 = alloca int\n\t%1 = add int 1, %0\n\tret void", "add operands disagree"},
+		{"b0:\n\t%0 = icmp eq int 1, bool true\n\tret void", "icmp operands disagree"},
+		{"b0:\n\t%0 = alloca int\n\tstore bool true, %0\n\tret void", "store of bool"},
+		{"b0:\n\tstore int 1, int 2\n\tret void", "store to non pointer"},
+		{"b0:\n\tbr b2\nb1:\n\tbr b2\nb2:\n\t%0 = phi [int 1, b0], [int 2, b1]\n\tret void", ""},
+		{"b0:\n\tbr b2\nb1:\n\tbr b2\nb2:\n\t%0 = phi [i8 1, b0], [int 2, b1]\n\tret void", "phi values disagree, i8 & int from b1"},
+		{"b0:\n\tbr b2\nb1:\n\tret void\nb2:\n\t%0 = phi [int 1, b0], [int 2, b1]\n\tret void", "phi names b1, which is not a predecessor"},
+		{"b0:\n\tcall @app::main::f(int 1)\n\tret void", "call to @app::main::f with 1 argument(s), expected 0"},
+		{"b0:\n\t%0 = add int 1, i32 2\n\tret void", "add operands disagree"},
+		{"b0:\n\t%0 = add int 1, lit.int 2\n\tret void", ""},
+	}
+
+	for _, test := range tests {
+		input := "module app::main\n\nfn @app::main::f() -> void {\n" + test.body + "\n}\n"
+		exec, err := Parse(input)
+		if err != nil {
+			t.Errorf("unexpected parse error %s\n%s", err, input)
+			continue
+		}
+
+		err = Validate(exec)
+
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("expected valid lir, found %s\n%s", err, input)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("expected error containing %q, validated successfully\n%s", test.err, input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, err)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package lirgen
 
 import (
-	"fmt"
-
 	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/lexer"
 	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/logging"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
 
@@ -16,20 +16,39 @@ type builder struct {
 	RFunctionEnums map[*lir.Function]*types.EnumVariant
 	MP             *lir.Executable
 	main           *lir.Function
+	log            *logging.Logger
+
+	targets []*jumpTarget           // the loops & switches enclosing the statement being visited, innermost last
+	labels  []*ast.LabeledStatement // the labeled statements enclosing the statement being visited, innermost last
+
+	closures map[*lir.Function]int           // the number of closures declared within a function, used to name them
+	thunks   map[*lir.Function]*lir.Function // maps named functions used as values to their thunk
+
+	errs      []error
+	conflicts map[string]bool // the libc functions whose conflicting declarations have been reported
 }
 
-func build(mod *lir.Module, mp *lir.Executable) error {
+func build(mod *lir.Module, mp *lir.Executable, log *logging.Logger) error {
 	b := &builder{
+		log:            log,
 		Mod:            mod,
 		Functions:      make(map[*ast.FunctionExpression]*lir.Function),
 		TFunctions:     make(map[types.Type]*lir.Function),
 		EnumFunctions:  make(map[*types.EnumVariant]*lir.Function),
 		RFunctionEnums: make(map[*lir.Function]*types.EnumVariant),
 		MP:             mp,
+		closures:       make(map[*lir.Function]int),
+		thunks:         make(map[*lir.Function]*lir.Function),
+		conflicts:      make(map[string]bool),
 	}
 
 	b.pass()
 	b.debugPrint()
+
+	if len(b.errs) != 0 {
+		return lexer.CombinedErrors(b.errs)
+	}
+
 	return nil
 }
 
@@ -81,6 +100,4 @@ func (b *builder) entry() {
 	fn.Emit(&lir.Return{
 		Result: lir.NewConst(int64(0), types.LookUp(types.Int8)),
 	})
-
-	fmt.Println("done", b.Mod.Name())
 }
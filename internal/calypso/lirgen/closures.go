@@ -0,0 +1,268 @@
+package lirgen
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+/*
+Closures are lowered to a function & a heap allocated environment
+
+	fn(a: int) -> int { return a + x; }
+
+becomes a function taking a pointer to its environment ahead of its parameters, the environment holds a pointer to that function followed by the values captured from the enclosing function.
+Captures are copies, composites included, as the environment may outlive the frame of the enclosing function.
+Environments are owned by no one, nothing frees them, so each closure expression that is evaluated leaks its environment until the program exits.
+A value of a function type is a pointer to the environment of a closure, so it is called by loading the function pointer at the head of the environment & passing it the environment.
+*/
+func (b *builder) evaluateClosureExpression(n *ast.FunctionExpression, fn *lir.Function, mod *lir.Module) lir.Value {
+	tFn := b.Mod.TModule.Table.GetClosure(n)
+	sg := tFn.Sg()
+
+	b.closures[fn]++
+	closure := lir.NewFunction(tFn)
+	closure.Name = fmt.Sprintf("%s::closure%d", fn.Name, b.closures[fn])
+
+	// Captures, constants are shared rather than stored in the environment
+	var captured []*types.Var
+	var values []lir.Value
+	var members []types.Type
+
+	for _, v := range tFn.Captures {
+		val, ok := fn.Variables[v.Name()]
+
+		if !ok {
+			panic(fmt.Sprintf("unable to locate captured variable, %s", v.Name()))
+		}
+
+		switch x := val.(type) {
+		case *lir.Constant:
+			closure.Variables[v.Name()] = x
+			continue
+		case *lir.Allocate, *lir.AccessStructProperty:
+			// Composites are copied into the environment from their address, the rest are loaded
+			if !isCompositeType(SafeDereference(x.Yields())) {
+				i := &lir.Load{
+					Address: x,
+				}
+
+				fn.Emit(i)
+				val = i
+			}
+		}
+
+		captured = append(captured, v)
+		values = append(values, val)
+		members = append(members, SafeDereference(val.Yields()))
+	}
+
+	env := b.emitEnvironmentComposite(closure.Name, sg, members)
+
+	// Environment & Parameters
+	self := &lir.Parameter{
+		Name:   "env",
+		Symbol: sg,
+		Parent: closure,
+	}
+	closure.Parameters = append(closure.Parameters, self)
+
+	for _, p := range sg.Parameters {
+		closure.AddParameter(p)
+	}
+
+	// Unpack Captures
+	record := &lir.BitCast{
+		Value: self,
+		To:    types.NewPointer(env.Type),
+	}
+	closure.Emit(record)
+
+	for idx, v := range captured {
+		ptr := &lir.AccessStructProperty{
+			Index:     idx + 1,
+			Address:   record,
+			Composite: env,
+		}
+		closure.Emit(ptr)
+
+		// composites are referenced by address, their copy lives in the environment
+		if isCompositeType(env.Members[idx+1]) {
+			closure.Variables[v.Name()] = ptr
+			continue
+		}
+
+		i := &lir.Load{
+			Address: ptr,
+		}
+		closure.Emit(i)
+		closure.Variables[v.Name()] = i
+	}
+
+	// Body, jumps do not cross the boundary of a closure
+	targets, labels := b.targets, b.labels
+	b.targets, b.labels = nil, nil
+	b.walkBody(n, closure)
+	b.targets, b.labels = targets, labels
+
+	b.Mod.Functions[closure.Name] = closure
+
+	return b.emitClosure(fn, closure, env, values)
+}
+
+// Named functions referenced outside of the target of a call are wrapped as closures
+func (b *builder) evaluateFunctionValue(val lir.Value, fn *lir.Function) lir.Value {
+	target, ok := val.(*lir.Function)
+
+	if !ok {
+		return val
+	}
+
+	return b.emitFunctionValue(target, fn)
+}
+
+// Wraps a named function, so it may be used as a value of a function type
+func (b *builder) emitFunctionValue(target *lir.Function, fn *lir.Function) lir.Value {
+	sg := target.Signature()
+
+	thunk, ok := b.thunks[target]
+
+	if !ok {
+		thunk = lir.NewFunction(target.TFunction)
+		thunk.Spec = target.Spec
+		thunk.Name = target.Name + "::thunk"
+
+		// Thunks of functions in other modules belong to this module
+		if b.Mod.Functions[target.Name] != target {
+			thunk.Name = b.Mod.TModule.SymbolName() + "::" + thunk.Name
+		}
+
+		thunk.Parameters = append(thunk.Parameters, &lir.Parameter{
+			Name:   "env",
+			Symbol: sg,
+			Parent: thunk,
+		})
+
+		for _, p := range sg.Parameters {
+			thunk.AddParameter(p)
+		}
+
+		// Add Call Graph Edge
+		g := b.MP.CallGraph
+		e := g.NewEdge(thunk, target)
+		g.SetEdge(e)
+
+		call := &lir.Call{
+			Target: target,
+		}
+
+		for _, p := range thunk.Parameters[1:] {
+			call.Arguments = append(call.Arguments, p)
+		}
+
+		thunk.Emit(call)
+
+		if sg.Result.Type() == types.LookUp(types.Void) {
+			thunk.Emit(&lir.ReturnVoid{})
+		} else {
+			thunk.Emit(&lir.Return{
+				Result: call,
+			})
+		}
+
+		b.thunks[target] = thunk
+		b.Mod.Functions[thunk.Name] = thunk
+	}
+
+	env := b.emitEnvironmentComposite(thunk.Name, sg, nil)
+	return b.emitClosure(fn, thunk, env, nil)
+}
+
+// Registers the composite of the environment of a closure, the function pointer followed by the types of the captured values
+func (b *builder) emitEnvironmentComposite(name string, sg *types.FunctionSignature, captures []types.Type) *lir.Composite {
+	name = name + "::env"
+
+	if c, ok := b.Mod.Composites[name]; ok {
+		return c
+	}
+
+	members := append([]types.Type{
+		&lir.FunctionPointer{Signature: sg},
+	}, captures...)
+
+	var fields []*types.Var
+	for i, m := range members {
+		fields = append(fields, types.NewVar(strconv.Itoa(i), m, b.Mod.TModule))
+	}
+
+	c := &lir.Composite{
+		Members: members,
+		Name:    name,
+		Type:    types.NewBaseDefinedType(name, types.NewStruct(fields), nil, nil, b.Mod.TModule),
+	}
+
+	b.Mod.Composites[c.Name] = c
+	b.MP.Composites[c.Type] = c
+	return c
+}
+
+// Allocates & populates the environment of a closure, yielding it as a value of the signature of the closure. The environment is never freed
+func (b *builder) emitClosure(fn *lir.Function, closure *lir.Function, env *lir.Composite, values []lir.Value) lir.Value {
+	addr := b.emitHeapAlloc(fn, env.Type)
+
+	for idx, v := range append([]lir.Value{closure}, values...) {
+		ptr := &lir.AccessStructProperty{
+			Index:     idx,
+			Address:   addr,
+			Composite: env,
+		}
+
+		fn.Emit(ptr)
+
+		if isCompositeType(env.Members[idx]) {
+			b.emitCompositeCopy(fn, ptr, v)
+			continue
+		}
+
+		b.emitStore(fn, ptr, v)
+	}
+
+	i := &lir.BitCast{
+		Value: addr,
+		To:    closure.Signature(),
+	}
+
+	fn.Emit(i)
+	return i
+}
+
+// Calls a value of a function type through the function pointer at the head of its environment
+func (b *builder) emitIndirectCall(n *ast.CallExpression, val lir.Value, sg *types.FunctionSignature, fn *lir.Function, mod *lir.Module) lir.Value {
+	ptr := &lir.BitCast{
+		Value: val,
+		To:    types.NewPointer(&lir.FunctionPointer{Signature: sg}),
+	}
+	fn.Emit(ptr)
+
+	callee := &lir.Load{
+		Address: ptr,
+	}
+	fn.Emit(callee)
+
+	args := []lir.Value{val}
+	for _, p := range n.Arguments {
+		v := b.evaluateExpression(p, fn, mod)
+		args = append(args, v)
+	}
+
+	i := &lir.IndirectCall{
+		Callee:    callee,
+		Arguments: args,
+	}
+
+	fn.Emit(i)
+	return b.emitCompositeResult(fn, i)
+}
@@ -1,6 +1,8 @@
 package lirgen
 
 import (
+	"fmt"
+
 	"github.com/mantton/calypso/internal/calypso/lir"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
@@ -13,6 +15,7 @@ func (b *builder) emitStackAlloc(fn *lir.Function, t types.Type) *lir.Allocate {
 	return i
 }
 
+// allocates a value which may outlive the frame of fn, heap allocations are owned by no function & are never freed
 func (b *builder) emitHeapAlloc(fn *lir.Function, t types.Type) *lir.Allocate {
 	i := &lir.Allocate{
 		TypeOf: t,
@@ -33,6 +36,80 @@ func (b *builder) emitLocalVar(fn *lir.Function, n string, t types.Type, addr *l
 	return addr
 }
 
+// Copies the struct at src into dst, member by member
+func (b *builder) emitCompositeCopy(fn *lir.Function, dst lir.Value, src lir.Value) {
+	composite := b.resolveCompositeOf(SafeDereference(src.Yields()), b.Mod)
+
+	for idx := range composite.Members {
+		from := &lir.AccessStructProperty{
+			Index:     idx,
+			Address:   src,
+			Composite: composite,
+		}
+		fn.Emit(from)
+
+		i := &lir.Load{
+			Address: from,
+		}
+		fn.Emit(i)
+
+		to := &lir.AccessStructProperty{
+			Index:     idx,
+			Address:   dst,
+			Composite: composite,
+		}
+		fn.Emit(to)
+		b.emitStore(fn, to, i)
+	}
+}
+
+// structs & enums with associated values, which are referenced by address
+func isCompositeType(t types.Type) bool {
+	return types.IsStruct(t.Parent()) || types.IsUnionEnum(t)
+}
+
+// Composites are returned by address into the frame of the callee, so they are copied into the frame of the caller once the call returns
+func (b *builder) emitCompositeResult(fn *lir.Function, v lir.Value) lir.Value {
+	if !isCompositeResult(v) {
+		return v
+	}
+
+	addr := b.emitStackAlloc(fn, v.Yields())
+	b.emitCompositeCopy(fn, addr, v)
+	return addr
+}
+
+func isCompositeResult(v lir.Value) bool {
+	switch v.(type) {
+	case *lir.Call, *lir.IndirectCall:
+		return types.IsStruct(v.Yields().Parent())
+	}
+
+	return false
+}
+
+// constants take the type of the value they are unified with, other values yield it already
+func convertConstant(v lir.Value, t types.Type) lir.Value {
+	c, ok := v.(*lir.Constant)
+
+	if !ok || c.Yields() == t {
+		return v
+	}
+
+	return lir.NewConst(c.Value, t)
+}
+
+// emits the value produced by an expression, unless the expression emitted it already e.g. calls & the phi of a nested conditional
+func (b *builder) emitValue(fn *lir.Function, v lir.Value) {
+	i, ok := v.(lir.Instruction)
+
+	if !ok || fn.Emitted(i) {
+		return
+	}
+
+	fn.Emit(i)
+}
+
 func (b *builder) emitStore(fn *lir.Function, addr lir.Value, val lir.Value) {
 	i := &lir.Store{
 		Address: addr,
@@ -51,3 +128,33 @@ func (b *builder) emitGlobalVar(m *lir.Module, c *lir.Constant, k string) {
 func (b *builder) emitConstantVar(fn *lir.Function, c *lir.Constant, k string) {
 	fn.Variables[k] = c
 }
+
+// emits a call to the method of the value at self, as it is resolved by name on its type
+func (b *builder) emitMethodCall(fn *lir.Function, self lir.Value, method string, args ...lir.Value) *lir.Call {
+	targetType := SafeDereference(self.Yields())
+
+	// Specialize
+	if fn.Spec != nil {
+		targetType = types.Instantiate(targetType, fn.Spec.Spec)
+	}
+
+	_, symbolType := types.ResolveSymbol(targetType, method)
+	target, ok := b.MP.Functions[symbolType]
+
+	if !ok {
+		panic(fmt.Sprintf("unable to locate method %s on %s", method, targetType))
+	}
+
+	// Add Call Graph Edge
+	g := b.MP.CallGraph
+	e := g.NewEdge(fn, target)
+	g.SetEdge(e)
+
+	i := &lir.Call{
+		Target:    target,
+		Arguments: append([]lir.Value{self}, args...),
+	}
+
+	fn.Emit(i)
+	return i
+}
@@ -2,6 +2,7 @@ package lirgen
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lir"
@@ -10,8 +11,8 @@ import (
 )
 
 func (b *builder) evaluateExpression(n ast.Expression, fn *lir.Function, mod *lir.Module) lir.Value {
-	fmt.Printf(
-		"\tVisiting Expression: %T @ Line %d\n",
+	b.log.Debugf(
+		"visiting expression: %T @ line %d",
 		n,
 		n.Range().Start.Line,
 	)
@@ -20,8 +21,9 @@ func (b *builder) evaluateExpression(n ast.Expression, fn *lir.Function, mod *li
 	case *ast.BooleanLiteral:
 		return lir.NewConst(e.Value, types.LookUp(types.Bool))
 	case *ast.StringLiteral:
-		// TODO: Global Composites
-		panic("string literals")
+		return lir.NewConst(e.Value, types.LookUp(types.String))
+	case *ast.InterpolatedStringLiteral:
+		return b.evaluateInterpolatedStringLiteral(e, fn, mod)
 	case *ast.CharLiteral:
 		return lir.NewConst(e.Value, types.LookUp(types.Char))
 	case *ast.IntegerLiteral:
@@ -29,7 +31,9 @@ func (b *builder) evaluateExpression(n ast.Expression, fn *lir.Function, mod *li
 		if typ == nil {
 			typ = types.LookUp(types.Int)
 		}
-		return lir.NewConst(e.Value, typ)
+
+		// constants hold the bits of the literal, u64 literals above the signed range wrap
+		return lir.NewConst(int64(e.Value), typ)
 	case *ast.FloatLiteral:
 		typ := b.Mod.TModule.Table.GetNodeType(n)
 
@@ -47,7 +51,7 @@ func (b *builder) evaluateExpression(n ast.Expression, fn *lir.Function, mod *li
 	case *ast.VoidLiteral:
 		return lir.NewConst(0, types.LookUp(types.Void))
 	case *ast.IdentifierExpression:
-		return b.evaluateIdentifierExpression(e, fn, mod)
+		return b.evaluateFunctionValue(b.evaluateIdentifierExpression(e, fn, mod), fn)
 	case *ast.CallExpression:
 		return b.evaluateCallExpression(e, fn, mod)
 	case *ast.CallArgument:
@@ -65,9 +69,16 @@ func (b *builder) evaluateExpression(n ast.Expression, fn *lir.Function, mod *li
 	case *ast.CompositeLiteral:
 		return b.evaluateCompositeLiteral(e, fn, mod)
 	case *ast.FieldAccessExpression:
-		return b.evaluateFieldAccessExpression(e, fn, mod, true)
+		return b.evaluateFunctionValue(b.evaluateFieldAccessExpression(e, fn, mod, true), fn)
 	case *ast.SpecializationExpression:
 		return b.evaluateSpecializationExpression(e, fn, mod)
+	case *ast.IfExpression:
+		return b.evaluateIfExpression(e, fn, mod)
+	case *ast.FunctionExpression:
+		return b.evaluateClosureExpression(e, fn, mod)
+	case *ast.TupleLiteral:
+		t := types.ResolveAliases(b.Mod.TModule.Table.GetNodeType(e)).(*types.Tuple)
+		return b.evaluateTupleLiteral(e, t, fn, mod)
 	default:
 		msg := fmt.Sprintf("unknown expr %T\n", e)
 		panic(msg)
@@ -81,7 +92,16 @@ func (b *builder) evaluateCallExpression(n *ast.CallExpression, fn *lir.Function
 		return b.emitEnumExpansion(n, fn)
 	}
 
-	val := b.evaluateExpression(n.Target, fn, mod)
+	// Named functions are called directly, rather than through a closure
+	var val lir.Value
+	switch t := n.Target.(type) {
+	case *ast.IdentifierExpression:
+		val = b.evaluateIdentifierExpression(t, fn, mod)
+	case *ast.FieldAccessExpression:
+		val = b.evaluateFieldAccessExpression(t, fn, mod, true)
+	default:
+		val = b.evaluateExpression(n.Target, fn, mod)
+	}
 
 	if val == nil {
 		panic(fmt.Sprintf("unable to locate target function for: %s", n.Target))
@@ -128,6 +148,11 @@ func (b *builder) evaluateCallExpression(n *ast.CallExpression, fn *lir.Function
 		args = append(args, val.Self)
 
 	default:
+		// Values of function types
+		if sg, ok := val.Yields().Parent().(*types.FunctionSignature); ok {
+			return b.emitIndirectCall(n, val, sg, fn, mod)
+		}
+
 		panic(fmt.Sprintf("unhandled call expression, %T", val))
 	}
 
@@ -151,7 +176,7 @@ func (b *builder) evaluateCallExpression(n *ast.CallExpression, fn *lir.Function
 	}
 
 	fn.Emit(i)
-	return i
+	return b.emitCompositeResult(fn, i)
 }
 
 func (b *builder) evaluateIdentifierExpression(n *ast.IdentifierExpression, fn *lir.Function, mod *lir.Module) lir.Value {
@@ -191,6 +216,9 @@ func (b *builder) evaluateIdentifierExpression(n *ast.IdentifierExpression, fn *
 			return val
 		case *lir.ExtractValue:
 			return val
+		case *lir.AccessStructProperty:
+			// composites captured by a closure, within its environment
+			return val
 		default:
 			panic(fmt.Sprintf("identifier found invalid type: %T", val))
 		}
@@ -602,6 +630,63 @@ func (b *builder) evaluateBooleanOp(op token.Token, n *ast.BinaryExpression, fn
 	return phi
 }
 
+func (b *builder) evaluateIfExpression(n *ast.IfExpression, fn *lir.Function, mod *lir.Module) lir.Value {
+	cond := b.evaluateExpression(n.Condition, fn, mod)
+
+	br := &lir.ConditionalBranch{
+		Condition: cond,
+	}
+	fn.Emit(br)
+
+	// Generate Blocks, the done block follows the blocks of the branches as its phi reads the values they produce
+	then := fn.NewBlock()
+	elseBlock := fn.NewBlock()
+
+	br.Action = then
+	br.Alternative = elseBlock
+
+	// the incoming values of the phi share the type of the expression, literals are converted to it
+	typ := types.ResolveLiteral(b.Mod.TModule.Table.GetNodeType(n))
+
+	// 1 - Action, the value is emitted within the branch & the branch may end in a block of its own e.g. nested conditionals
+	fn.CurrentBlock = then
+	action := convertConstant(b.evaluateExpression(n.Action, fn, mod), typ)
+	b.emitValue(fn, action)
+	actionEnd := fn.CurrentBlock
+
+	// 2 - Alternative
+	fn.CurrentBlock = elseBlock
+	alternative := convertConstant(b.evaluateExpression(n.Alternative, fn, mod), typ)
+	b.emitValue(fn, alternative)
+	alternativeEnd := fn.CurrentBlock
+
+	done := fn.NewBlock()
+	actionEnd.Emit(&lir.Branch{
+		Block: done,
+	})
+	alternativeEnd.Emit(&lir.Branch{
+		Block: done,
+	})
+
+	// 3 - Done Block, Use Phi to pick value based on which branch executed prior
+	fn.CurrentBlock = done
+	phi := &lir.PHI{
+		Nodes: []*lir.PhiNode{
+			{
+				Value: action,
+				Block: actionEnd,
+			},
+			{
+				Value: alternative,
+				Block: alternativeEnd,
+			},
+		},
+	}
+
+	fn.Emit(phi)
+	return phi
+}
+
 func (b *builder) evaluateAddressOfExpression(n ast.Expression, fn *lir.Function, mod *lir.Module) lir.Value {
 	switch n := n.(type) {
 	case *ast.IdentifierExpression:
@@ -632,6 +717,11 @@ func (b *builder) evaluateAddressOfExpression(n ast.Expression, fn *lir.Function
 	case *ast.FieldAccessExpression:
 		x := b.evaluateFieldAccessExpression(n, fn, mod, false)
 		return x
+	case *ast.GroupedExpression:
+		return b.evaluateAddressOfExpression(n.Expr, fn, mod)
+	case *ast.CallExpression, *ast.TupleLiteral:
+		// composites are yielded by address
+		return b.evaluateExpression(n, fn, mod)
 	default:
 		panic(fmt.Sprintf("unimplmented address of, %T", n))
 	}
@@ -749,6 +839,51 @@ func (b *builder) evaluateCompositeLiteral(n *ast.CompositeLiteral, fn *lir.Func
 	return addr
 }
 
+func (b *builder) evaluateTupleLiteral(n *ast.TupleLiteral, t *types.Tuple, fn *lir.Function, mod *lir.Module) lir.Value {
+	composite := lir.NewTupleComposite(t)
+	addr := b.emitStackAlloc(fn, t)
+
+	for idx, e := range n.Elements {
+		var value lir.Value
+		element := t.Elements[idx]
+
+		// Nested literals take the type of their element
+		if literal, ok := e.(*ast.TupleLiteral); ok {
+			value = b.evaluateTupleLiteral(literal, types.ResolveAliases(element).(*types.Tuple), fn, mod)
+		} else {
+			value = b.evaluateExpression(e, fn, mod)
+		}
+
+		switch v := value.(type) {
+		case *lir.Constant:
+			// Literals take the type of their element
+			if v.Yields() != element {
+				value = lir.NewConst(v.Value, element)
+			}
+		case *lir.Allocate:
+			// Composite elements are stored by value
+			if types.IsStruct(v.TypeOf.Parent()) {
+				i := &lir.Load{
+					Address: v,
+				}
+				fn.Emit(i)
+				value = i
+			}
+		}
+
+		ptr := &lir.AccessStructProperty{
+			Index:     idx,
+			Address:   addr,
+			Composite: composite,
+		}
+
+		fn.Emit(ptr)
+		b.emitStore(fn, ptr, value)
+	}
+
+	return addr
+}
+
 func (b *builder) evaluateFieldAccessExpression(n *ast.FieldAccessExpression, fn *lir.Function, mod *lir.Module, load bool) lir.Value {
 	// 1 - Evaluate Address or Type Reference
 	target := b.evaluateAddressOfExpression(n.Target, fn, mod)
@@ -757,6 +892,9 @@ func (b *builder) evaluateFieldAccessExpression(n *ast.FieldAccessExpression, fn
 	switch p := n.Field.(type) {
 	case *ast.IdentifierExpression:
 		field = p.Value
+	case *ast.IntegerLiteral:
+		// elements of a tuple are named by their index
+		field = strconv.FormatUint(p.Value, 10)
 	default:
 		if target, ok := target.(*lir.Module); ok {
 			tgt := b.evaluateExpression(p, fn, target)
@@ -822,7 +960,7 @@ func (b *builder) evaluateFieldAccessExpression(n *ast.FieldAccessExpression, fn
 		if fn.Spec != nil {
 			targetType = types.Instantiate(targetType, fn.Spec.Spec)
 		}
-		fmt.Println("\t\tAccessing", field, "on", targetType, fmt.Sprintf("%T", target))
+		b.log.Debugf("accessing %s on %s, %T", field, targetType, target)
 	}
 
 	symbol, symbolType := types.ResolveSymbol(targetType, field)
@@ -905,6 +1043,8 @@ func (b *builder) resolveCompositeOf(t types.Type, mod *lir.Module) *lir.Composi
 	switch t := t.(type) {
 	case *types.Pointer:
 		return b.resolveCompositeOf(t.PointerTo, mod)
+	case *types.Tuple:
+		return lir.NewTupleComposite(t)
 	case *types.SpecializedType:
 		c := mod.Composites[t.SymbolName()]
 
@@ -1,8 +1,6 @@
 package lirgen
 
 import (
-	"fmt"
-
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lir"
 	"github.com/mantton/calypso/internal/calypso/types"
@@ -45,7 +43,7 @@ func (b *builder) registerFunction(n *ast.FunctionExpression) {
 		panic("function node not type checked")
 	}
 
-	fmt.Println("Registering", tFn.SymbolName())
+	b.log.Debugf("registering %s", tFn.SymbolName())
 	if types.IsGeneric(sg) {
 		b.registerMonomorphicSpecializations(sg.Function)
 		return
@@ -84,7 +82,7 @@ func (b *builder) registerMonomorphicSpecializations(fn *types.Function) {
 		b.MP.CallGraph.AddNode(sFn) // CallGraph Add Node
 		b.MP.Functions[ssg] = sFn   // Add to Program Scope
 
-		fmt.Println("\tRegistered", ssg.SymbolName())
+		b.log.Debugf("registered specialization %s", ssg.SymbolName())
 	}
 
 	b.Mod.GFunctions[fn.SymbolName()] = gFn
@@ -132,7 +130,7 @@ func (b *builder) visitFunction(n *ast.FunctionExpression) {
 }
 
 func (b *builder) walkFunction(n *ast.FunctionExpression, fn *lir.Function) {
-	fmt.Println("\nWalking", fn.Name)
+	b.log.Debugf("walking %s", fn.Name)
 	fn.AddSelf()
 
 	// Parameters
@@ -149,6 +147,10 @@ func (b *builder) walkFunction(n *ast.FunctionExpression, fn *lir.Function) {
 		return
 	}
 
+	b.walkBody(n, fn)
+}
+
+func (b *builder) walkBody(n *ast.FunctionExpression, fn *lir.Function) {
 	// Body
 	stmts := n.Body.Statements
 
@@ -157,12 +159,31 @@ func (b *builder) walkFunction(n *ast.FunctionExpression, fn *lir.Function) {
 		return
 	}
 
+	b.emitParameterCopies(fn)
+
 	// Statements
 	for _, stmt := range stmts {
 		b.visitStatement(stmt, fn)
 	}
-	fmt.Println()
 
+	// void functions may fall through their final block
+	if !fn.CurrentBlock.Complete && fn.Signature().Result.Type() == types.LookUp(types.Void) {
+		fn.Emit(&lir.ReturnVoid{})
+	}
+
+}
+
+// Structs are passed by address, so the callee copies them into its frame, leaving the value of the caller untouched
+func (b *builder) emitParameterCopies(fn *lir.Function) {
+	for _, p := range fn.Parameters {
+		if !types.IsStruct(p.Symbol.Parent()) {
+			continue
+		}
+
+		addr := b.emitStackAlloc(fn, p.Symbol)
+		b.emitCompositeCopy(fn, addr, p)
+		fn.Variables[p.Name] = addr
+	}
 }
 
 func (b *builder) walkMonomorphizations(fn *types.Function) {
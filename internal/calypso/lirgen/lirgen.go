@@ -3,15 +3,18 @@ package lirgen
 import (
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/logging"
+	"github.com/mantton/calypso/internal/calypso/typechecker"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
 
-func Generate(packages []*ast.Package, tmap *types.PackageMap) (*lir.Executable, error) {
+func Generate(packages []*ast.Package, tmap *types.PackageMap, log *logging.Logger) (*lir.Executable, error) {
 
 	exec := lir.NewExecutable()
+	log = log.For("lirgen")
 
 	for _, pkg := range packages {
-		err := genPackage(pkg, tmap, exec)
+		err := genPackage(pkg, tmap, exec, log)
 
 		if err != nil {
 			return nil, err
@@ -20,7 +23,24 @@ func Generate(packages []*ast.Package, tmap *types.PackageMap) (*lir.Executable,
 	return exec, nil
 }
 
-func genPackage(p *ast.Package, mp *types.PackageMap, e *lir.Executable) error {
+// Typechecks & generates a single file, see typechecker.PackageString
+func GenerateString(str string) (*lir.Executable, error) {
+	pkg, err := typechecker.PackageString(str)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mp, err := typechecker.CheckPackages([]*ast.Package{pkg}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Generate([]*ast.Package{pkg}, mp, nil)
+}
+
+func genPackage(p *ast.Package, mp *types.PackageMap, e *lir.Executable, log *logging.Logger) error {
 
 	// Add pkg
 	pkg := lir.NewPackage(p)
@@ -31,7 +51,8 @@ func genPackage(p *ast.Package, mp *types.PackageMap, e *lir.Executable) error {
 		tMod := mp.Modules[m.ID()]
 		mod := lir.NewModule(tMod)
 
-		err := build(mod, e)
+		log.Infof("generating module %s", m.Key())
+		err := build(mod, e, log)
 
 		if err != nil {
 			return err
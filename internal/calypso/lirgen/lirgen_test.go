@@ -0,0 +1,469 @@
+package lirgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/interp"
+	"github.com/mantton/calypso/internal/calypso/lir"
+)
+
+// generates, validates & interprets a program, returning its exit code & output
+func run(t *testing.T, input string) (int, string) {
+	t.Helper()
+	exec, err := GenerateString(input)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lir.Validate(exec)
+
+	if err != nil {
+		t.Fatalf("%s\n\n%s", err, lir.PrintExecutable(exec))
+	}
+
+	for _, m := range exec.Modules {
+		for _, fn := range m.Functions {
+			emittedOnce(t, fn)
+		}
+	}
+
+	out := &strings.Builder{}
+	code, err := interp.Run(exec, interp.Options{Stdout: out})
+
+	if err != nil {
+		t.Fatalf("%s\n\n%s", err, lir.PrintExecutable(exec))
+	}
+
+	return code, out.String()
+}
+
+// every instruction is placed in a single block, once
+func emittedOnce(t *testing.T, fn *lir.Function) {
+	t.Helper()
+	placed := make(map[lir.Instruction]*lir.Block)
+
+	for _, blk := range fn.Blocks {
+		for _, i := range blk.Instructions {
+			if prev, ok := placed[i]; ok {
+				t.Errorf("@%s emits %T in b%d, it is already placed in b%d", fn.Name, i, blk.Index, prev.Index)
+			}
+
+			placed[i] = blk
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+			fn puts(_ s: string) -> i32;
+		}
+
+		standard Stringable {
+			fn toString() -> string;
+		}
+
+		conform int to Stringable {
+			fn toString() -> string {
+				return "int";
+			}
+		}
+
+		struct Foo {
+			Value: int;
+		}
+
+		conform Foo to Stringable {
+			fn toString() -> string {
+				return "Foo(\(self.Value))";
+			}
+		}
+
+		fn main() {
+			const name = "world";
+			const count = 0;
+			puts("hello \(name), \(count) \("nested \(count + 1)")");
+
+			const foo = Foo { Value: 1 };
+			const plain = "\(foo)";
+			puts(plain);
+			exit(0);
+		}
+	`
+
+	code, out := run(t, input)
+
+	if code != 0 {
+		t.Errorf("expected exit code 0, found %d", code)
+	}
+
+	expected := "hello world, int nested int\nFoo(int)\n"
+	if out != expected {
+		t.Errorf("expected output %q, found %q", expected, out)
+	}
+}
+
+func TestStringLibcDeclarations(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+			fn strlen(_ s: string) -> int;
+		}
+
+		fn main() {
+			const name = "world";
+			const s = "hello \(name)";
+			exit(strlen(s));
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 11 {
+		t.Errorf("expected exit code 11, found %d", code)
+	}
+
+	_, err := GenerateString(`
+		module main;
+
+		extern "C" {
+			fn strlen(_ s: string) -> i32;
+		}
+
+		fn main() {
+			const name = "world";
+			const s = "hello \(name)";
+			const r = "\(s)!";
+		}
+	`)
+
+	if err == nil || strings.Count(err.Error(), "conflicting declaration of `strlen` in module main") != 1 {
+		t.Errorf("expected the conflicting declaration of strlen to be reported once, found %v", err)
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+			fn putchar(_ c: int) -> int;
+		}
+
+		fn mark(_ c: int) -> int {
+			putchar(c);
+			return c;
+		}
+
+		fn pick(_ a: int) -> int {
+			return if a > 10 { mark(97) } else if a > 4 { mark(98) + 1 } else { 3 };
+		}
+
+		fn main() {
+			exit(pick(20) + pick(5) + pick(1));
+		}
+	`
+
+	code, out := run(t, input)
+
+	if code != 97+99+3 {
+		t.Errorf("expected exit code %d, found %d", 97+99+3, code)
+	}
+
+	// each call in a branch is made once
+	if out != "ab" {
+		t.Errorf("expected output %q, found %q", "ab", out)
+	}
+}
+
+func TestIfExpressionConversion(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+		}
+
+		fn pick(_ a: int) -> i8 {
+			let b: i8 = if a > 1 { 1 } else { 2 };
+			return b;
+		}
+
+		fn narrow(_ a: int, _ z: i8) -> i8 {
+			let y: i8 = if a > 1 { z } else { 2 };
+			return y;
+		}
+
+		fn byte(_ a: int) -> u8 {
+			return if a > 1 { 200 } else if a > 0 { 100 } else { 50 };
+		}
+
+		fn main() {
+			let w: u8 = 7;
+			const c = if w > 5 { 250 } else { w };
+
+			let code = 0;
+			if (pick(5) == 1) {
+				code += 1;
+			}
+
+			if (narrow(0, pick(5)) == 2) {
+				code += 2;
+			}
+
+			if (byte(5) == 200 && byte(1) == 100) {
+				code += 4;
+			}
+
+			if (c == 250) {
+				code += 8;
+			}
+
+			exit(code);
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 15 {
+		t.Errorf("expected exit code 15, found %d", code)
+	}
+
+	exec, err := GenerateString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the annotation is the type of the local
+	for _, m := range exec.Modules {
+		for _, fn := range m.Functions {
+			if strings.HasSuffix(fn.Name, "::pick") && strings.Contains(lir.PrintFunction(fn), "alloca int") {
+				t.Errorf("expected b to be allocated as an i8\n\n%s", lir.PrintFunction(fn))
+			}
+		}
+	}
+}
+
+func TestClosureCaptures(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+		}
+
+		struct Point {
+			x: int;
+			y: int;
+		}
+
+		fn reader(_ x: int, _ y: int) -> fn() -> int {
+			const p = Point { x: x, y: y };
+			return fn () -> int {
+				return p.x * 10 + p.y;
+			};
+		}
+
+		fn clobber(_ a: int, _ b: int) -> int {
+			const q = Point { x: a, y: b };
+			const r = Point { x: b, y: a };
+			return q.x + r.y;
+		}
+
+		fn twice(_ f: fn(int) -> int, _ v: int) -> int {
+			return f(f(v));
+		}
+
+		fn inc(_ v: int) -> int {
+			return v + 1;
+		}
+
+		fn main() {
+			// the environment outlives the frame of reader
+			const f = reader(4, 2);
+			clobber(7, 9);
+
+			// the capture is a copy, later writes are not seen
+			let p = Point { x: 1, y: 1 };
+			const g = fn () -> int {
+				return p.x;
+			};
+			p.x = 5;
+
+			const nested = fn () -> int {
+				const inner = fn () -> int { return p.y; };
+				return inner();
+			};
+
+			const step = 3;
+			const add = fn (v: int) -> int { return v + step; };
+
+			exit(f() + g() + nested() + twice(add, 0) + twice(inc, 0)); // 42 + 1 + 1 + 6 + 2
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 52 {
+		t.Errorf("expected exit code 52, found %d", code)
+	}
+}
+
+func TestTuples(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+		}
+
+		fn divide(_ a: int, by b: int) -> (int, bool) {
+			if (b == 0) {
+				return (0, false);
+			}
+
+			return (a / b, true);
+		}
+
+		fn pair(_ a: int, _ b: int) -> (int, int) {
+			return (a, b);
+		}
+
+		// reuses the stack of the frames it follows
+		fn clobber() -> int {
+			const t = (9, 9);
+			const u = pair(8, 8);
+			return t.0 + u.1 - 17;
+		}
+
+		fn join(_ p: (int, int), _ pad: int) -> int {
+			return p.0 * 10 + p.1 + pad;
+		}
+
+		fn main() {
+			let (q, ok) = divide(84, by: 2);
+			let (_, bad) = divide(1, by: 0);
+			let n = ((1, 2), 3);
+			n.0.1 = 5;
+
+			let total = 0;
+			if (ok && !bad) {
+				total = q + n.0.1 + n.1;
+			}
+
+			// results are copied into the frame of the caller
+			let p = pair(1, 2);
+			clobber();
+			exit(total + join(p, 0) + join(pair(4, 1), clobber())); // 50 + 12 + 41
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 103 {
+		t.Errorf("expected exit code 103, found %d", code)
+	}
+
+	exec, err := GenerateString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tuples live in the frame that declares them
+	for _, m := range exec.Modules {
+		for _, fn := range m.Functions {
+			for _, blk := range fn.Blocks {
+				for _, i := range blk.Instructions {
+					if a, ok := i.(*lir.Allocate); ok && a.OnHeap {
+						t.Errorf("@%s allocates %s on the heap", fn.Name, a.TypeOf)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTupleCaptures(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+		}
+
+		fn maker(_ a: int) -> fn() -> int {
+			const t = (a, a + 1);
+			return fn () -> int {
+				return t.0 + t.1;
+			};
+		}
+
+		fn main() {
+			const f = maker(3);
+			let t = (1, 2);
+			const g = fn () -> int { return t.1; };
+			t.1 = 7;
+			exit(f() + g()); // 7 + 2
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 9 {
+		t.Errorf("expected exit code 9, found %d", code)
+	}
+}
+
+func TestStructArguments(t *testing.T) {
+	input := `
+		module main;
+
+		extern "C" {
+			fn exit(_ code: int);
+		}
+
+		struct Point {
+			x: int;
+			y: int;
+		}
+
+		// writes to a parameter are not seen by the caller
+		fn bump(_ p: Point) -> int {
+			p.x = 40;
+			return p.x;
+		}
+
+		fn make(_ x: int) -> Point {
+			return Point { x: x, y: 2 };
+		}
+
+		fn main() {
+			let p = Point { x: 1, y: 2 };
+			const b = bump(p);
+
+			// each result is a copy
+			let a = make(3);
+			const c = make(4);
+			a.x = 10;
+
+			const f = fn (q: Point) -> int { q.y = 50; return q.y; };
+			const d = f(p);
+
+			exit(b + p.x + p.y + a.x + c.x + d); // 40 + 1 + 2 + 10 + 4 + 50
+		}
+	`
+
+	code, _ := run(t, input)
+
+	if code != 107 {
+		t.Errorf("expected exit code 107, found %d", code)
+	}
+}
@@ -5,18 +5,19 @@ import (
 
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/token"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
 
 func (b *builder) visitStatement(node ast.Statement, fn *lir.Function) {
 
 	if fn.CurrentBlock.Complete {
-		fmt.Println("Statements after return statement are not executed")
+		b.log.Debugf("unreachable statement: %T @ line %d", node, node.Range().Start.Line)
 		return
 	}
 
-	fmt.Printf(
-		"Visiting Statement: %T @ Line %d\n",
+	b.log.Debugf(
+		"visiting statement: %T @ line %d",
 		node,
 		node.Range().Start.Line,
 	)
@@ -37,7 +38,17 @@ func (b *builder) visitStatement(node ast.Statement, fn *lir.Function) {
 		b.visitSwitchStatement(node, fn)
 	case *ast.WhileStatement:
 		b.visitWhileStatement(node, fn)
-	case *ast.BreakStatement, *ast.StructStatement, *ast.EnumStatement, *ast.TypeStatement:
+	case *ast.ForStatement:
+		b.visitForStatement(node, fn)
+	case *ast.ForInStatement:
+		b.visitForInStatement(node, fn)
+	case *ast.BreakStatement:
+		b.visitBreakStatement(node, fn)
+	case *ast.ContinueStatement:
+		b.visitContinueStatement(node, fn)
+	case *ast.LabeledStatement:
+		b.visitLabeledStatement(node, fn)
+	case *ast.StructStatement, *ast.EnumStatement, *ast.TypeStatement:
 		break
 	case *ast.DereferenceAssignmentStatement:
 		b.visitDerefAssignmentStatement(node, fn)
@@ -50,6 +61,11 @@ func (b *builder) visitStatement(node ast.Statement, fn *lir.Function) {
 func (b *builder) visitVariableStatement(n *ast.VariableStatement, fn *lir.Function) {
 	val := b.evaluateExpression(n.Value, fn, b.Mod)
 
+	if n.Identifier == nil {
+		b.visitDestructuringStatement(n, val, fn)
+		return
+	}
+
 	if n.IsConstant {
 		v, ok := val.(*lir.Constant)
 
@@ -68,6 +84,33 @@ func (b *builder) visitVariableStatement(n *ast.VariableStatement, fn *lir.Funct
 	}
 }
 
+// Binds each element of a tuple to a local, `_` discards the element
+func (b *builder) visitDestructuringStatement(n *ast.VariableStatement, val lir.Value, fn *lir.Function) {
+	t := types.ResolveAliases(SafeDereference(val.Yields())).(*types.Tuple)
+	composite := lir.NewTupleComposite(t)
+
+	for idx, ident := range n.Elements {
+		if ident.Value == "_" {
+			continue
+		}
+
+		ptr := &lir.AccessStructProperty{
+			Index:     idx,
+			Address:   val,
+			Composite: composite,
+		}
+		fn.Emit(ptr)
+
+		i := &lir.Load{
+			Address: ptr,
+		}
+		fn.Emit(i)
+
+		addr := b.emitLocalVar(fn, ident.Value, t.Elements[idx], nil)
+		b.emitStore(fn, addr, i)
+	}
+}
+
 func (b *builder) visitReturnStatement(n *ast.ReturnStatement, fn *lir.Function) {
 	val := b.evaluateExpression(n.Value, fn, b.Mod)
 
@@ -83,13 +126,7 @@ func (b *builder) visitReturnStatement(n *ast.ReturnStatement, fn *lir.Function)
 }
 
 func (b *builder) visitExpressionStatement(n *ast.ExpressionStatement, fn *lir.Function) {
-	i, ok := b.evaluateExpression(n.Expr, fn, b.Mod).(lir.Instruction)
-
-	if !ok {
-		return
-	}
-
-	fn.Emit(i)
+	b.emitValue(fn, b.evaluateExpression(n.Expr, fn, b.Mod))
 }
 
 func (b *builder) visitBlockStatement(n *ast.BlockStatement, fn *lir.Function) {
@@ -109,31 +146,37 @@ func (b *builder) visitIfStatement(n *ast.IfStatement, fn *lir.Function) {
 
 	// Generate Blocks
 	then := fn.NewBlock()
-	var elseBlock *lir.Block
-	if n.Alternative != nil {
-		elseBlock = fn.NewBlock()
-	}
-	done := fn.NewBlock()
-
 	br.Action = then
-	if elseBlock != nil {
-		br.Alternative = elseBlock
-	} else {
-		br.Alternative = then
-	}
 
 	// Action
 	fn.CurrentBlock = then
 	b.visitBlockStatement(n.Action, fn)
-	fn.Emit(&lir.Branch{
-		Block: done,
-	})
+	ends := []*lir.Block{fn.CurrentBlock}
 
 	// Alternative
 	if n.Alternative != nil {
+		elseBlock := fn.NewBlock()
+		br.Alternative = elseBlock
+
 		fn.CurrentBlock = elseBlock
-		b.visitBlockStatement(n.Alternative, fn)
-		fn.Emit(&lir.Branch{
+		b.visitStatement(n.Alternative, fn)
+		ends = append(ends, fn.CurrentBlock)
+
+		// every branch returns or jumps, nothing follows the statement
+		if ends[0].Complete && ends[1].Complete {
+			return
+		}
+	}
+
+	// the "Done" block, following the statement
+	done := fn.NewBlock()
+
+	if n.Alternative == nil {
+		br.Alternative = done
+	}
+
+	for _, end := range ends {
+		end.Emit(&lir.Branch{
 			Block: done,
 		})
 	}
@@ -143,11 +186,21 @@ func (b *builder) visitIfStatement(n *ast.IfStatement, fn *lir.Function) {
 
 func (b *builder) visitWhileStatement(n *ast.WhileStatement, fn *lir.Function) {
 
+	entry := fn.CurrentBlock
+
 	// Setup Blocks
 	loop := fn.NewBlock() // Checks the Condition
 	body := fn.NewBlock() // Body of While loop
 	done := fn.NewBlock() // Exit of while loop
 
+	b.enterTarget(n, loop)
+	defer b.exitTarget(done)
+
+	// Enter Loop
+	entry.Emit(&lir.Branch{
+		Block: loop,
+	})
+
 	// Emit Condition
 	fn.CurrentBlock = loop
 	cond := b.evaluateExpression(n.Condition, fn, b.Mod)
@@ -167,6 +220,140 @@ func (b *builder) visitWhileStatement(n *ast.WhileStatement, fn *lir.Function) {
 	fn.CurrentBlock = done
 }
 
+func (b *builder) visitForStatement(n *ast.ForStatement, fn *lir.Function) {
+
+	// Init
+	if n.Init != nil {
+		b.visitStatement(n.Init, fn)
+	}
+
+	entry := fn.CurrentBlock
+
+	// Setup Blocks
+	loop := fn.NewBlock() // Checks the Condition
+	body := fn.NewBlock() // Body of for loop
+	post := fn.NewBlock() // Steps the loop
+	done := fn.NewBlock() // Exit of for loop
+
+	b.enterTarget(n, post)
+	defer b.exitTarget(done)
+
+	// Enter Loop
+	entry.Emit(&lir.Branch{
+		Block: loop,
+	})
+
+	// Emit Condition, a missing condition loops until the body returns
+	fn.CurrentBlock = loop
+	if n.Condition != nil {
+		cond := b.evaluateExpression(n.Condition, fn, b.Mod)
+		fn.Emit(&lir.ConditionalBranch{
+			Condition:   cond,
+			Action:      body,
+			Alternative: done,
+		})
+	} else {
+		fn.Emit(&lir.Branch{
+			Block: body,
+		})
+	}
+
+	// Emit Body
+	fn.CurrentBlock = body
+	b.visitBlockStatement(n.Action, fn)
+	fn.Emit(&lir.Branch{
+		Block: post,
+	})
+
+	// Emit Post
+	fn.CurrentBlock = post
+	if n.Post != nil {
+		b.visitExpressionStatement(&ast.ExpressionStatement{Expr: n.Post}, fn)
+	}
+	fn.Emit(&lir.Branch{
+		Block: loop,
+	})
+
+	fn.CurrentBlock = done
+}
+
+// Lowers a loop over an `Iterable` collection to a counted loop, fetching the element at each index from the collection
+func (b *builder) visitForInStatement(n *ast.ForInStatement, fn *lir.Function) {
+
+	// Address of the collection, the receiver of the iterable methods
+	var self lir.Value
+	switch n.Collection.(type) {
+	case *ast.IdentifierExpression, *ast.FieldAccessExpression:
+		self = b.evaluateAddressOfExpression(n.Collection, fn, b.Mod)
+	default:
+		val := b.evaluateExpression(n.Collection, fn, b.Mod)
+		addr, ok := val.(*lir.Allocate)
+
+		if !ok {
+			addr = b.emitStackAlloc(fn, val.Yields())
+			b.emitStore(fn, addr, val)
+		}
+
+		self = addr
+	}
+
+	intType := types.LookUp(types.Int)
+	count := b.emitMethodCall(fn, self, "count")
+	index := b.emitStackAlloc(fn, intType)
+	b.emitStore(fn, index, lir.NewConst(int64(0), intType))
+
+	entry := fn.CurrentBlock
+
+	// Setup Blocks
+	loop := fn.NewBlock() // Checks the Index
+	body := fn.NewBlock() // Body of for loop
+	post := fn.NewBlock() // Steps the Index
+	done := fn.NewBlock() // Exit of for loop
+
+	b.enterTarget(n, post)
+	defer b.exitTarget(done)
+
+	// Enter Loop
+	entry.Emit(&lir.Branch{
+		Block: loop,
+	})
+
+	// Emit Condition
+	fn.CurrentBlock = loop
+	fn.Emit(&lir.ConditionalBranch{
+		Condition: &lir.ICmp{
+			Left:       &lir.Load{Address: index},
+			Right:      count,
+			Comparison: lir.SOpMap[token.L_CHEVRON],
+		},
+		Action:      body,
+		Alternative: done,
+	})
+
+	// Emit Body
+	fn.CurrentBlock = body
+	element := b.emitMethodCall(fn, self, "element", &lir.Load{Address: index})
+	addr := b.emitLocalVar(fn, n.Element.Value, element.Yields(), nil)
+	b.emitStore(fn, addr, element)
+
+	b.visitBlockStatement(n.Action, fn)
+	fn.Emit(&lir.Branch{
+		Block: post,
+	})
+
+	// Emit Post
+	fn.CurrentBlock = post
+	b.emitStore(fn, index, &lir.Add{
+		Left:  &lir.Load{Address: index},
+		Right: lir.NewConst(int64(1), intType),
+	})
+	fn.Emit(&lir.Branch{
+		Block: loop,
+	})
+
+	fn.CurrentBlock = done
+}
+
 func (b *builder) visitSwitchStatement(n *ast.SwitchStatement, fn *lir.Function) {
 	cond := b.evaluateExpression(n.Condition, fn, b.Mod)
 
@@ -207,6 +394,8 @@ func (b *builder) visitSwitchStatement(n *ast.SwitchStatement, fn *lir.Function)
 	}
 	fn.Emit(instr)
 
+	b.enterTarget(n, nil)
+
 	var defaultCase *ast.SwitchCaseExpression
 
 	for _, cs := range n.Cases {
@@ -247,6 +436,7 @@ func (b *builder) visitSwitchStatement(n *ast.SwitchStatement, fn *lir.Function)
 	}
 
 	done = fn.NewBlock()
+	b.exitTarget(done)
 
 	// No Default Case, set default to next block after switch statement, our "done" block
 	if instr.Done == nil {
@@ -286,3 +476,80 @@ func (b *builder) visitDerefAssignmentStatement(n *ast.DereferenceAssignmentStat
 
 	fn.Emit(str)
 }
+
+// a loop or switch `break` & `continue` statements branch out of
+type jumpTarget struct {
+	node   ast.Statement
+	breaks []*lir.Branch // branches to the exit of the statement, pointed at the exit once it is emitted
+	latch  *lir.Block    // the block `continue` statements branch to, nil for switches
+}
+
+func (b *builder) enterTarget(n ast.Statement, latch *lir.Block) {
+	b.targets = append(b.targets, &jumpTarget{
+		node:  n,
+		latch: latch,
+	})
+}
+
+func (b *builder) exitTarget(exit *lir.Block) {
+	t := b.targets[len(b.targets)-1]
+	b.targets = b.targets[:len(b.targets)-1]
+
+	for _, br := range t.breaks {
+		br.Block = exit
+	}
+}
+
+// the target of a `break` or `continue` statement, the innermost target unless labeled
+func (b *builder) resolveTarget(label *ast.IdentifierExpression, isContinue bool) *jumpTarget {
+	var node ast.Statement
+
+	if label != nil {
+		for i := len(b.labels) - 1; i >= 0; i-- {
+			if b.labels[i].Label.Value == label.Value {
+				node = b.labels[i].Stmt
+				break
+			}
+		}
+	}
+
+	for i := len(b.targets) - 1; i >= 0; i-- {
+		t := b.targets[i]
+
+		if node != nil && t.node != node || isContinue && t.latch == nil {
+			continue
+		}
+
+		return t
+	}
+
+	panic("unable to locate target of jump")
+}
+
+func (b *builder) visitLabeledStatement(n *ast.LabeledStatement, fn *lir.Function) {
+	b.labels = append(b.labels, n)
+	defer func() {
+		b.labels = b.labels[:len(b.labels)-1]
+	}()
+
+	b.visitStatement(n.Stmt, fn)
+}
+
+func (b *builder) visitBreakStatement(n *ast.BreakStatement, fn *lir.Function) {
+	t := b.resolveTarget(n.Label, false)
+
+	br := &lir.Branch{}
+	t.breaks = append(t.breaks, br)
+
+	fn.Emit(br)
+	fn.CurrentBlock.Complete = true
+}
+
+func (b *builder) visitContinueStatement(n *ast.ContinueStatement, fn *lir.Function) {
+	t := b.resolveTarget(n.Label, true)
+
+	fn.Emit(&lir.Branch{
+		Block: t.latch,
+	})
+	fn.CurrentBlock.Complete = true
+}
@@ -0,0 +1,127 @@
+package lirgen
+
+import (
+	"fmt"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/lir"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+// Lowers an interpolated string to the concatenation of its parts, values that are not strings are converted with their `Stringable` conformance
+func (b *builder) evaluateInterpolatedStringLiteral(n *ast.InterpolatedStringLiteral, fn *lir.Function, mod *lir.Module) lir.Value {
+	stringType := types.LookUp(types.String)
+	parts := []lir.Value{}
+
+	for _, part := range n.Parts {
+		val := b.evaluateExpression(part, fn, mod)
+
+		if types.ResolveAliases(val.Yields()) != stringType {
+			val = b.emitMethodCall(fn, val, "toString")
+		}
+
+		parts = append(parts, val)
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return b.emitStringConcat(fn, parts)
+}
+
+// Emits the concatenation of strings into a single heap allocation
+func (b *builder) emitStringConcat(fn *lir.Function, parts []lir.Value) lir.Value {
+	stringType := types.LookUp(types.String)
+	sizeType := types.LookUp(types.UInt64)
+
+	strlen := b.libc("strlen", sizeType, stringType)
+	malloc := b.libc("malloc", stringType, sizeType)
+	strcpy := b.libc("strcpy", stringType, stringType, stringType)
+	strcat := b.libc("strcat", stringType, stringType, stringType)
+
+	// 1 - Measure, reserving the nul terminator
+	var size lir.Value = lir.NewConst(int64(1), sizeType)
+	for _, part := range parts {
+		length := b.emitCall(fn, strlen, part)
+		add := &lir.Add{
+			Left:  size,
+			Right: length,
+		}
+		fn.Emit(add)
+		size = add
+	}
+
+	// 2 - Copy each part in order
+	buffer := b.emitCall(fn, malloc, size)
+	b.emitCall(fn, strcpy, buffer, parts[0])
+
+	for _, part := range parts[1:] {
+		b.emitCall(fn, strcat, buffer, part)
+	}
+
+	return buffer
+}
+
+func (b *builder) emitCall(fn *lir.Function, target *lir.Function, args ...lir.Value) *lir.Call {
+	i := &lir.Call{
+		Target:    target,
+		Arguments: args,
+	}
+
+	fn.Emit(i)
+	return i
+}
+
+// returns the libc function of the module with the given name, declaring it if the module has not. Declarations which do not agree with the libc signature are reported rather than reused
+func (b *builder) libc(name string, result types.Type, params ...types.Type) *lir.Function {
+	sg := types.NewFunctionSignature()
+	sg.Result.SetType(result)
+	for _, p := range params {
+		sg.AddParameter(types.NewVar("", p, nil))
+	}
+
+	tfn := types.NewFunction(name, sg, nil)
+	tfn.Target = &types.FunctionTarget{
+		Target: "C",
+	}
+
+	fn := lir.NewFunction(tfn)
+	fn.Name = name
+	fn.External = true
+
+	for _, p := range sg.Parameters {
+		fn.AddParameter(p)
+	}
+
+	if prev, ok := b.Mod.Functions[name]; ok {
+		if prev.External && libcAgrees(prev.Signature(), sg) {
+			return prev
+		}
+
+		if !b.conflicts[name] {
+			b.errs = append(b.errs, fmt.Errorf("\nconflicting declaration of `%s` in module %s, found %s, expected %s", name, b.Mod.Name(), prev.Signature(), sg))
+			b.conflicts[name] = true
+		}
+
+		return fn
+	}
+
+	b.Mod.Functions[name] = fn
+	return fn
+}
+
+// reports whether a declaration of a libc function takes & returns values with the representation of its signature, regardless of their labels
+func libcAgrees(decl, sg *types.FunctionSignature) bool {
+	if len(decl.Parameters) != len(sg.Parameters) {
+		return false
+	}
+
+	for i, p := range decl.Parameters {
+		if !lir.Agree(p.Type(), sg.Parameters[i].Type()) {
+			return false
+		}
+	}
+
+	return lir.Agree(decl.Result.Type(), sg.Result.Type())
+}
@@ -1,8 +1,11 @@
+//go:build !nollvm
+
 package llir
 
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 
 	"github.com/mantton/calypso/internal/calypso/lexer"
 	"github.com/mantton/calypso/internal/calypso/lir"
@@ -18,11 +21,47 @@ func init() {
 	llvm.InitializeAllAsmPrinters()
 }
 
+// The artifact produced by Compile
+type Artifact byte
+
+const (
+	Executable Artifact = iota
+	LLVMIR
+	Bitcode
+	Assembly
+	Object
+)
+
+type Options struct {
+	Output   string                                  // path of the produced artifact
+	BuildDir string                                  // directory intermediate files are written to
+	Artifact Artifact                                // the artifact to produce
+	Write    func(path string, content []byte) error // writes the artifact, defaults to os.WriteFile
+}
+
+func (o Options) write(path string, content []byte) error {
+	if o.Write == nil {
+		return os.WriteFile(path, content, 0644)
+	}
+
+	return o.Write(path, content)
+}
+
 type GCompiler struct {
 	modules map[int64]llvm.Module
 }
 
-func Compile(s *lir.Executable) error {
+func Compile(s *lir.Executable, opts Options) error {
+
+	err := os.MkdirAll(opts.BuildDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(opts.Output), 0755)
+	if err != nil {
+		return err
+	}
 
 	gc := &GCompiler{
 		modules: make(map[int64]llvm.Module),
@@ -30,8 +69,6 @@ func Compile(s *lir.Executable) error {
 	errs := []error{}
 	ctx := llvm.NewContext()
 
-	files := []string{}
-
 	// Generate LLVM Modules
 	for _, pkg := range s.Packages {
 		for _, mod := range pkg.Modules {
@@ -49,10 +86,9 @@ func Compile(s *lir.Executable) error {
 	}
 
 	// Combine
-	for _, pkg := range s.Packages {
-
-		base := ctx.NewModule(pkg.AST.Name())
+	base := ctx.NewModule("combined")
 
+	for _, pkg := range s.Packages {
 		for _, mod := range pkg.Modules {
 			llvmMod := gc.modules[mod.ID()]
 			err := llvm.LinkModules(base, llvmMod)
@@ -60,84 +96,95 @@ func Compile(s *lir.Executable) error {
 			if err != nil {
 				return err
 			}
-
 		}
+	}
 
-		err := llvm.VerifyModule(base, llvm.ReturnStatusAction)
+	err = llvm.VerifyModule(base, llvm.ReturnStatusAction)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		trg, err := llvm.GetTargetFromTriple(llvm.DefaultTargetTriple())
-		if err != nil {
-			errs = append(errs, err)
-		}
-		base.SetTarget(trg.Description())
+	triple := llvm.DefaultTargetTriple()
+	trg, err := llvm.GetTargetFromTriple(triple)
 
-		// mt := trg.CreateTargetMachine(llvm.DefaultTargetTriple(), "", "", llvm.CodeGenLevelDefault, llvm.RelocDefault, llvm.CodeModelDefault)
+	if err != nil {
+		return err
+	}
 
-		// pbo := llvm.NewPassBuilderOptions()
-		// defer pbo.Dispose()
+	mt := trg.CreateTargetMachine(triple, "", "", llvm.CodeGenLevelDefault, llvm.RelocPIC, llvm.CodeModelDefault)
+	defer mt.Dispose()
 
-		// pm := llvm.NewPassManager()
-		// mt.AddAnalysisPasses(pm)
+	td := mt.CreateTargetData()
+	base.SetTarget(triple)
+	base.SetDataLayout(td.String())
+	td.Dispose()
 
-		// err = base.RunPasses("default<Os>", mt, pbo)
+	// pbo := llvm.NewPassBuilderOptions()
+	// defer pbo.Dispose()
 
-		// if err != nil {
-		// 	return err
-		// }
+	// err = base.RunPasses("default<Os>", mt, pbo)
 
-		// fmt.Println("\n\n")
-		// base.Dump()
+	// if err != nil {
+	// 	return err
+	// }
 
-		f, err := os.Create("./bin/" + pkg.AST.Name() + ".bc")
+	switch opts.Artifact {
+	case LLVMIR:
+		return opts.write(opts.Output, []byte(base.String()))
+	case Bitcode:
+		buf := llvm.WriteBitcodeToMemoryBuffer(base)
+		defer buf.Dispose()
+		return opts.write(opts.Output, buf.Bytes())
+	case Assembly:
+		asm, err := emit(mt, base, llvm.AssemblyFile)
 		if err != nil {
 			return err
 		}
 
-		err = llvm.WriteBitcodeToFile(base, f)
-
+		return opts.write(opts.Output, asm)
+	case Object:
+		obj, err := emit(mt, base, llvm.ObjectFile)
 		if err != nil {
 			return err
 		}
 
-		files = append(files, f.Name())
+		return opts.write(opts.Output, obj)
 	}
 
-	// Link Packages
-	combined, err := os.Create("./bin/combined.bc")
+	// Create Executable
+	obj := filepath.Join(opts.BuildDir, "combined.o")
+	err = emitToFile(mt, base, llvm.ObjectFile, obj)
 
 	if err != nil {
 		return err
 	}
 
-	args := []string{}
-	args = append(args, files...)
-	args = append(args, "-o")
-	args = append(args, combined.Name())
-
-	cmd := exec.Command("llvm-link", args...)
+	cmd := exec.Command("clang", obj, "-o", opts.Output)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	return cmd.Run()
+}
+
+func emit(mt llvm.TargetMachine, m llvm.Module, ft llvm.CodeGenFileType) ([]byte, error) {
+	buf, err := mt.EmitToMemoryBuffer(m, ft)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create Executable
-	cmd = exec.Command("clang", combined.Name(), "-o", "./bin/exec")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	defer buf.Dispose()
+	return buf.Bytes(), nil
+}
+
+func emitToFile(mt llvm.TargetMachine, m llvm.Module, ft llvm.CodeGenFileType, path string) error {
+	content, err := emit(mt, m, ft)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return os.WriteFile(path, content, 0644)
 }
 
 type compiler struct {
@@ -146,12 +193,14 @@ type compiler struct {
 	lirMod     *lir.Module
 	exec       *lir.Executable
 	typesTable map[types.Type]llvm.Type
+	strings    map[string]llvm.Value // the globals holding the string constants of the module
 }
 
 func newCompiler(module *lir.Module, exec *lir.Executable, ctx llvm.Context) *compiler {
 	c := &compiler{
 		context:    ctx,
 		typesTable: make(map[types.Type]llvm.Type),
+		strings:    make(map[string]llvm.Value),
 		exec:       exec,
 	}
 
@@ -191,6 +240,11 @@ func (c *compiler) compileModule() (llvm.Module, error) {
 }
 
 func (c *compiler) buildComposite(cm *lir.Composite) llvm.Type {
+	// composites may be built ahead of their turn, as members of another
+	if t, ok := c.typesTable[cm.Type]; ok {
+		return t
+	}
+
 	members := []llvm.Type{}
 
 	for _, t := range cm.Members {
@@ -198,6 +252,13 @@ func (c *compiler) buildComposite(cm *lir.Composite) llvm.Type {
 		members = append(members, typ)
 	}
 
+	// anonymous composites are literal structs, identified by their members
+	if cm.Name == "" {
+		llvmType := c.context.StructType(members, false)
+		c.typesTable[cm.Type] = llvmType
+		return llvmType
+	}
+
 	llvmType := c.context.StructCreateNamed(cm.Name)
 	c.typesTable[cm.Type] = llvmType
 	llvmType.StructSetBody(members, false)
@@ -0,0 +1,186 @@
+//go:build !nollvm
+
+package llir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/fs"
+	"github.com/mantton/calypso/internal/calypso/lirgen"
+	"github.com/mantton/calypso/internal/calypso/parser"
+	"github.com/mantton/calypso/internal/calypso/typechecker"
+	"tinygo.org/x/go-llvm"
+)
+
+// parses, checks & generates a single file, returning its verified llvm modules
+func compileString(t *testing.T, input string) []llvm.Module {
+	t.Helper()
+	file, errs := parser.ParseString(input)
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	cfg := &fs.Config{}
+	cfg.Package.Name = file.ModuleName
+	pkg := ast.NewPackage(fs.NewPackage("", cfg))
+	pkg.IsTarget = true
+
+	m := ast.NewModule(nil, pkg)
+	m.Set = &ast.FileSet{ModuleName: file.ModuleName, Files: []*ast.File{file}}
+	pkg.AddModule(m)
+
+	mp, err := typechecker.CheckPackages([]*ast.Package{pkg}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exec, err := lirgen.Generate([]*ast.Package{pkg}, mp, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := llvm.NewContext()
+	modules := []llvm.Module{}
+	for _, mod := range exec.Modules {
+		lMod, err := newCompiler(mod, exec, ctx).compileModule()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		modules = append(modules, lMod)
+	}
+
+	return modules
+}
+
+func TestVoidFunctions(t *testing.T) {
+	input := `
+		module main;
+
+		fn touch(_ a: int) {
+			let b = a;
+			b = b + 1;
+		}
+
+		fn main() {
+			touch(1);
+		}
+	`
+
+	for _, mod := range compileString(t, input) {
+		fn := mod.NamedFunction("main::main::touch")
+
+		if fn.IsNil() {
+			t.Fatalf("expected touch to be compiled\n%s", mod.String())
+		}
+
+		// locals are allocated as their own type
+		entry := fn.FirstBasicBlock()
+		if alloca := entry.FirstInstruction(); alloca.IsAAllocaInst().IsNil() || alloca.AllocatedType() != mod.Context().Int64Type() {
+			t.Errorf("expected touch to allocate an i64 for b\n%s", mod.String())
+		}
+
+		// void functions return at the end of their body
+		if ret := fn.LastBasicBlock().LastInstruction(); ret.IsAReturnInst().IsNil() {
+			t.Errorf("expected touch to end in ret void\n%s", mod.String())
+		}
+	}
+}
+
+func TestHeapAllocations(t *testing.T) {
+	input := `
+		module main;
+
+		struct Pair {
+			A: int;
+			B: int;
+		}
+
+		fn make(_ a: int) -> int {
+			const p = Pair { A: a, B: 2 };
+			return p.A + p.B;
+		}
+
+		fn main() {
+			make(1);
+		}
+	`
+
+	for _, mod := range compileString(t, input) {
+		fn := mod.NamedFunction("main::main::make")
+
+		if fn.IsNil() {
+			t.Fatalf("expected make to be compiled\n%s", mod.String())
+		}
+
+		// struct literals are allocated on the heap, rather than in the frame of make
+		mallocs, allocas := 0, 0
+		for blk := fn.FirstBasicBlock(); !blk.IsNil(); blk = llvm.NextBasicBlock(blk) {
+			for i := blk.FirstInstruction(); !i.IsNil(); i = llvm.NextInstruction(i) {
+				if !i.IsACallInst().IsNil() && i.CalledValue().Name() == "malloc" {
+					mallocs++
+				}
+
+				if !i.IsAAllocaInst().IsNil() && i.AllocatedType().TypeKind() == llvm.StructTypeKind {
+					allocas++
+				}
+			}
+		}
+
+		if mallocs != 1 || allocas != 0 {
+			t.Errorf("expected the literal to be allocated with malloc, found %d malloc call(s) & %d struct alloca(s)\n%s", mallocs, allocas, mod.String())
+		}
+	}
+}
+
+// structs & tuples are passed & returned by address, matching the lowering of the interpreter
+func TestStructABI(t *testing.T) {
+	input := `
+		module main;
+
+		struct Point {
+			x: int;
+			y: int;
+		}
+
+		fn sum(_ p: Point) -> int {
+			return p.x + p.y;
+		}
+
+		fn make(_ x: int) -> Point {
+			return Point { x: x, y: 2 };
+		}
+
+		fn swap(_ p: (int, bool)) -> (bool, int) {
+			return (p.1, p.0);
+		}
+
+		fn main() {
+			const p = make(40);
+			const s = sum(p);
+			const t = swap((1, true));
+		}
+	`
+
+	signatures := []string{
+		`define i64 @"main::main::sum"(%"main::main::Point"* %0)`,
+		`define %"main::main::Point"* @"main::main::make"(i64 %0)`,
+		`define { i1, i64 }* @"main::main::swap"({ i64, i1 }* %0)`,
+	}
+
+	for _, mod := range compileString(t, input) {
+		ir := mod.String()
+
+		for _, sg := range signatures {
+			if !strings.Contains(ir, sg) {
+				t.Errorf("expected %s\n\n%s", sg, ir)
+			}
+		}
+	}
+}
@@ -1,3 +1,5 @@
+//go:build !nollvm
+
 package llir
 
 import (
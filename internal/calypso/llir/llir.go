@@ -1 +1,3 @@
+//go:build !nollvm
+
 package llir
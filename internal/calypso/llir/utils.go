@@ -1,3 +1,5 @@
+//go:build !nollvm
+
 package llir
 
 import (
@@ -55,6 +57,8 @@ func (c *compiler) createConstant(n *lir.Constant) llvm.Value {
 			return llvm.ConstPointerNull(c.context.Int1Type())
 		case types.Float, types.FloatLiteral:
 			return llvm.ConstFloat(c.context.FloatType(), n.Value.(float64))
+		case types.String:
+			return c.createString(n.Value.(string))
 		default:
 			panic("basic type constant type has not been defined yet")
 		}
@@ -66,6 +70,24 @@ func (c *compiler) createConstant(n *lir.Constant) llvm.Value {
 	}
 }
 
+// returns the address of a string constant, its bytes are held by a private global of the module
+func (c *compiler) createString(s string) llvm.Value {
+	if v, ok := c.strings[s]; ok {
+		return v
+	}
+
+	data := c.context.ConstString(s, true)
+	global := llvm.AddGlobal(c.module, data.Type(), "")
+	global.SetInitializer(data)
+	global.SetGlobalConstant(true)
+	global.SetLinkage(llvm.PrivateLinkage)
+	global.SetUnnamedAddr(true)
+
+	v := llvm.ConstBitCast(global, llvm.PointerType(c.context.Int8Type(), 0))
+	c.strings[s] = v
+	return v
+}
+
 func (c *compiler) getType(t types.Type) llvm.Type {
 	v, ok := c.typesTable[t]
 
@@ -98,12 +120,13 @@ func (c *compiler) getType(t types.Type) llvm.Type {
 		case types.NilLiteral:
 			panic("INVALID")
 		case types.String:
-			panic("String Impl")
+			// nul terminated bytes
+			return llvm.PointerType(c.context.Int8Type(), 0)
 		default:
 			panic(fmt.Sprintf("unhandled basic type, %d", t.Literal))
 		}
 	case *types.Struct, *types.Enum:
-		composite, ok := c.exec.Composites[p]
+		composite, ok := c.exec.CompositeOf(p)
 
 		if !ok {
 			panic(fmt.Sprintf("cannot find composite for %s", p))
@@ -119,12 +142,47 @@ func (c *compiler) getType(t types.Type) llvm.Type {
 	case *lir.StaticArray:
 		element := c.getType(t.OfType)
 		return llvm.ArrayType(element, int(t.Count))
+	case *types.FunctionSignature:
+		// closures point to their environment
+		return llvm.PointerType(c.context.Int8Type(), 0)
+	case *lir.FunctionPointer:
+		return llvm.PointerType(c.getClosureFunctionType(t.Signature), 0)
 	default:
 		panic(fmt.Sprintf("Unsupported Type: %T, %s", t, t))
 	}
 
 }
 
+// the type of the function of a closure, which takes its environment ahead of the parameters of its signature
+func (c *compiler) getClosureFunctionType(sg *types.FunctionSignature) llvm.Type {
+	retType := c.getType(sg.Result.Type())
+
+	params := []llvm.Type{
+		llvm.PointerType(c.context.Int8Type(), 0),
+	}
+
+	for _, param := range sg.Parameters {
+		params = append(params, c.getParameterType(param.Type()))
+	}
+
+	if _, ok := c.exec.CompositeOf(sg.Result.Type()); ok {
+		retType = llvm.PointerType(retType, 0)
+	}
+
+	return llvm.FunctionType(retType, params, false)
+}
+
+// structs are passed by address, as they are returned
+func (c *compiler) getParameterType(t types.Type) llvm.Type {
+	typ := c.getType(t)
+
+	if _, ok := c.exec.CompositeOf(t); ok && types.IsStruct(t.Parent()) {
+		return llvm.PointerType(typ, 0)
+	}
+
+	return typ
+}
+
 func (c *compiler) getFunction(fn *lir.Function) (llvm.Value, llvm.Type) {
 	llvmFn := c.module.NamedFunction(fn.Name)
 
@@ -137,11 +195,11 @@ func (c *compiler) getFunction(fn *lir.Function) (llvm.Value, llvm.Type) {
 	var params []llvm.Type
 
 	for _, param := range fn.Parameters {
-		t := c.getType(param.Symbol)
+		t := c.getParameterType(param.Symbol)
 		params = append(params, t)
 	}
 
-	if _, ok := c.exec.Composites[sg.Result.Type()]; ok {
+	if _, ok := c.exec.CompositeOf(sg.Result.Type()); ok {
 		retType = llvm.PointerType(retType, 0)
 	}
 
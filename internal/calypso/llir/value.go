@@ -1,3 +1,5 @@
+//go:build !nollvm
+
 package llir
 
 import (
@@ -34,6 +36,9 @@ func (b *builder) createValue(v lir.Value) llvm.Value {
 		return b.compiler.createConstant(v)
 	case *lir.Global:
 		return b.compiler.createConstant(v.Value)
+	case *lir.Function:
+		fn, _ := b.getFunction(v)
+		return fn
 	case *lir.Add:
 		lhs, rhs := b.getValue(v.Left), b.getValue(v.Right)
 		return b.CreateAdd(lhs, rhs, "")
@@ -101,6 +106,8 @@ func (b *builder) createValue(v lir.Value) llvm.Value {
 		return b.CreateLShr(lhs, rhs, "")
 	case *lir.Call:
 		return b.createCall(v)
+	case *lir.IndirectCall:
+		return b.createIndirectCall(v)
 	case *lir.PHI:
 		return b.createPhi(v)
 	case *lir.Load:
@@ -113,6 +120,8 @@ func (b *builder) createValue(v lir.Value) llvm.Value {
 		return b.createExtractValue(v)
 	case *lir.PointerOffset:
 		return b.createPointerOffset(v)
+	case *lir.BitCast:
+		return b.CreateBitCast(b.getValue(v.Value), b.getType(v.To), "")
 	default:
 		msg := fmt.Sprintf("[LLIRGEN] Value not implemented, %T", v)
 		panic(msg)
@@ -148,8 +157,13 @@ func (b *builder) createLoad(v *lir.Load) llvm.Value {
 }
 
 func (b *builder) createAlloc(v *lir.Allocate) llvm.Value {
-	// TODO: Heap/Stack
-	typ := b.compiler.getType(v.Yields())
+	typ := b.compiler.getType(v.TypeOf)
+
+	// values that may outlive their frame are allocated with malloc, nothing frees them so they live until the program exits
+	if v.OnHeap {
+		return b.CreateMalloc(typ, "")
+	}
+
 	addr := b.CreateAlloca(typ, "")
 	return addr
 }
@@ -166,6 +180,18 @@ func (b *builder) createCall(v *lir.Call) llvm.Value {
 	return r
 }
 
+func (b *builder) createIndirectCall(v *lir.IndirectCall) llvm.Value {
+	fn := b.getValue(v.Callee)
+	fnType := b.getClosureFunctionType(v.Callee.Yields().(*lir.FunctionPointer).Signature)
+	var lA []llvm.Value
+
+	for _, p := range v.Arguments {
+		lA = append(lA, b.getValue(p))
+	}
+
+	return b.CreateCall(fnType, fn, lA, "")
+}
+
 func (b *builder) createStructFieldAccess(v *lir.AccessStructProperty) llvm.Value {
 	addr := b.getValue(v.Address)
 
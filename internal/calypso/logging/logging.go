@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Level byte
+
+const (
+	Silent Level = iota
+	Info         // stages of the pipeline, `-v`
+	Debug        // per node tracing, `-vv`
+)
+
+/*
+A leveled logger for tracing the compiler
+
+A nil logger is silent, components can be traced at the debug level regardless of the verbosity using `--trace=typechecker,lirgen`
+*/
+type Logger struct {
+	out       io.Writer
+	level     Level
+	traced    map[string]bool
+	component string
+}
+
+func New(out io.Writer, level Level, traced []string) *Logger {
+	l := &Logger{
+		out:    out,
+		level:  level,
+		traced: make(map[string]bool),
+	}
+
+	for _, c := range traced {
+		c = strings.TrimSpace(c)
+		if c != "" {
+			l.traced[c] = true
+		}
+	}
+
+	return l
+}
+
+// Returns a logger scoped to a component of the compiler
+func (l *Logger) For(component string) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	return &Logger{
+		out:       l.out,
+		level:     l.level,
+		traced:    l.traced,
+		component: component,
+	}
+}
+
+// Reports whether messages at the provided level would be written
+func (l *Logger) Enabled(level Level) bool {
+	if l == nil {
+		return false
+	}
+
+	return l.level >= level || l.traced[l.component]
+}
+
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(Info, format, args...)
+}
+
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(Debug, format, args...)
+}
+
+func (l *Logger) logf(level Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	if l.component != "" {
+		msg = fmt.Sprintf("[%s] %s", l.component, msg)
+	}
+
+	fmt.Fprintln(l.out, strings.TrimRight(msg, "\n"))
+}
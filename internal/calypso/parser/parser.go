@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/cst"
 	"github.com/mantton/calypso/internal/calypso/fs"
 	"github.com/mantton/calypso/internal/calypso/lexer"
 	"github.com/mantton/calypso/internal/calypso/token"
@@ -22,7 +23,7 @@ type Parser struct {
 	file   *lexer.File
 	errors lexer.ErrorList
 
-	inSwitch bool
+	inHeader bool // parsing the header of a loop or if expression, where a brace opens the body rather than a composite literal
 	cursor   int
 
 	modifiers []token.Token
@@ -38,6 +39,13 @@ func ParseString(input string) (*ast.File, lexer.ErrorList) {
 	return p.Parse(), p.errors
 }
 
+// Parses a string scanned with trivia, into the lossless syntax tree of the file
+func ParseSyntaxTree(input string) (*cst.Node, lexer.ErrorList) {
+	lF := lexer.NewFileFromStringWithMode(input, lexer.ScanTrivia)
+	p := New(lF)
+	return cst.Build(p.Parse()), p.errors
+}
+
 func (p *Parser) Parse() *ast.File {
 	moduleName := ""
 
@@ -49,6 +57,13 @@ func (p *Parser) Parse() *ast.File {
 	defer func() {
 		file.Errors = p.errors
 	}()
+
+	// tokens of a file with lexical errors cannot be trusted, report the lexical errors alone
+	if len(p.file.Errors) != 0 {
+		p.errors = append(p.errors, p.file.Errors...)
+		return file
+	}
+
 	// - Parse Module Declaration
 
 	// Module Header
@@ -96,6 +111,10 @@ func (p *Parser) TestParse() (*ast.File, error) {
 		LexerFile:  p.file,
 	}
 
+	if len(p.file.Errors) != 0 {
+		return nil, errors.New(p.file.Errors.String())
+	}
+
 	for p.current() != token.EOF {
 		decl, err := p.parseDeclaration()
 		if err != nil {
@@ -107,7 +126,6 @@ func (p *Parser) TestParse() (*ast.File, error) {
 	}
 
 	if len(p.errors) != 0 {
-		fmt.Println(p.errors)
 		return nil, errors.New(file.Errors.String())
 	}
 	return file, nil
@@ -192,7 +210,7 @@ func ParseFileSet(set *fs.FileSet) (*ast.FileSet, error) {
 		if files.ModuleName == "" {
 			files.ModuleName = file.ModuleName
 		} else if files.ModuleName != file.ModuleName {
-			return nil, fmt.Errorf("multiple modules in the same directory: %s, %s", files.ModuleName, file.ModuleName)
+			return nil, fmt.Errorf("multiple modules in the same directory: %s, %s (%s)", files.ModuleName, file.ModuleName, path)
 		}
 
 		files.Files = append(files.Files, file)
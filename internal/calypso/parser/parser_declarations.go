@@ -30,11 +30,13 @@ func (p *Parser) parseDeclaration() (ast.Declaration, error) {
 		stmt, err := p.parseVariableStatement()
 
 		if err != nil {
-			fmt.Println("err", err)
-
 			return nil, err
 		}
 
+		if stmt.Identifier == nil {
+			return nil, p.errorAt("top level constants cannot be destructured", stmt.Range())
+		}
+
 		stmt.IsGlobal = true
 		return &ast.ConstantDeclaration{
 			Stmt: stmt,
@@ -121,6 +123,7 @@ func (p *Parser) parseStandardDeclaration() (*ast.StandardDeclaration, error) {
 	if err != nil {
 		p.errors.Add(err)
 	}
+	doc := p.leadingDoc()
 	keyw, err := p.expect(token.STANDARD)
 
 	if err != nil {
@@ -143,6 +146,7 @@ func (p *Parser) parseStandardDeclaration() (*ast.StandardDeclaration, error) {
 		Identifier: ident,
 		Block:      block,
 		Visibility: vis,
+		Doc:        doc,
 	}, nil
 }
 
@@ -367,9 +371,10 @@ func (p *Parser) parseImportDeclaration() (*ast.ImportDeclaration, error) {
 		return nil, err
 	}
 
-	path := &ast.StringLiteral{
-		Value: pathTok.Lit,
-		Pos:   pathTok.Pos,
+	path, err := p.parseStringLiteral(pathTok)
+
+	if err != nil {
+		return nil, err
 	}
 
 	decl.Path = path
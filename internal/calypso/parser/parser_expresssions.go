@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/token"
@@ -19,7 +20,7 @@ func (p *Parser) parseBinaryExpression() (ast.Expression, error) {
 // =. +=, -=, *=, /=, %=
 // &=, |=,  ^=,  <<=,  >>=
 func (p *Parser) parseAssignmentExpression() (ast.Expression, error) {
-	expr, err := p.parseDoubleBarExpression()
+	expr, err := p.parseBinaryOperation(1)
 
 	if err != nil {
 		return nil, err
@@ -27,7 +28,7 @@ func (p *Parser) parseAssignmentExpression() (ast.Expression, error) {
 
 	if p.match(token.ASSIGN) {
 		pos := p.previousScannedToken().Pos
-		value, err := p.parseDoubleBarExpression()
+		value, err := p.parseBinaryOperation(1)
 
 		if err != nil {
 			return nil, err
@@ -44,7 +45,7 @@ func (p *Parser) parseAssignmentExpression() (ast.Expression, error) {
 		token.BIT_SHIFT_LEFT_EQ, token.BIT_SHIFT_RIGHT_EQ) {
 
 		op := p.previousScannedToken()
-		value, err := p.parseDoubleBarExpression()
+		value, err := p.parseBinaryOperation(1)
 
 		if err != nil {
 			return nil, err
@@ -61,261 +62,40 @@ func (p *Parser) parseAssignmentExpression() (ast.Expression, error) {
 	return expr, nil
 }
 
-// || Boolean OR
-func (p *Parser) parseDoubleBarExpression() (ast.Expression, error) {
-	expr, err := p.parseDoubleAmpExpression()
+/*
+Binary operators, parsed by precedence climbing
 
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.DOUBLE_BAR) {
-		op := p.previousScannedToken()
-		right, err := p.parseDoubleAmpExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-
-}
-
-// &&
-func (p *Parser) parseDoubleAmpExpression() (ast.Expression, error) {
-	expr, err := p.parseEqualityExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.DOUBLE_AMP) {
-		op := p.previousScannedToken()
-		right, err := p.parseEqualityExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-
-}
-
-// ==, !=
-func (p *Parser) parseEqualityExpression() (ast.Expression, error) {
-	expr, err := p.parseComparisonExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.NEQ, token.EQL) {
-		op := p.previousScannedToken()
-		right, err := p.parseComparisonExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-}
-
-// <, >, <=, >=
-func (p *Parser) parseComparisonExpression() (ast.Expression, error) {
-	expr, err := p.parseBitwiseORExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.R_CHEVRON, token.GEQ, token.LEQ, token.L_CHEVRON) {
-		op := p.previousScannedToken()
-		right, err := p.parseBitwiseORExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-}
-
-func (p *Parser) parseBitwiseORExpression() (ast.Expression, error) {
-	expr, err := p.parseBitwiseXORExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.BAR) {
-		op := p.previousScannedToken()
-		right, err := p.parseBitwiseXORExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-
-}
-
-func (p *Parser) parseBitwiseXORExpression() (ast.Expression, error) {
-	expr, err := p.parseBitwiseANDExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.CARET) {
-		op := p.previousScannedToken()
-		right, err := p.parseBitwiseANDExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-
-}
-
-func (p *Parser) parseBitwiseANDExpression() (ast.Expression, error) {
-	expr, err := p.parseBitwiseShiftExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.AMP) {
-		op := p.previousScannedToken()
-		right, err := p.parseBitwiseShiftExpression()
-
-		if err != nil {
-			return nil, err
-		}
-
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
-		}
-	}
-
-	return expr, nil
-
-}
-
-func (p *Parser) parseBitwiseShiftExpression() (ast.Expression, error) {
-	expr, err := p.parseTermExpression()
+The right operand of an operator is parsed with the operators binding tighter than it, operators of the same precedence
+are then folded into the left operand making every level left-associative, `a - b - c` is `(a - b) - c`.
+*/
+func (p *Parser) parseBinaryOperation(precedence int) (ast.Expression, error) {
+	expr, err := p.parseUnaryExpression()
 
 	if err != nil {
 		return nil, err
 	}
 
-	if p.match(token.BIT_SHIFT_LEFT, token.BIT_SHIFT_RIGHT) {
-		op := p.previousScannedToken()
-		right, err := p.parseTermExpression()
-
-		if err != nil {
-			return nil, err
-		}
+	for {
+		op := p.currentScannedToken()
+		opPrecedence := token.BinaryPrecedent[op.Tok]
 
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
+		if opPrecedence == 0 || opPrecedence < precedence {
+			return expr, nil
 		}
-	}
-
-	return expr, nil
-}
-
-// +, -
-func (p *Parser) parseTermExpression() (ast.Expression, error) {
-	expr, err := p.parseFactorExpression()
 
-	if err != nil {
-		return nil, err
-	}
+		p.next()
 
-	for p.match(token.MINUS, token.PLUS) {
-		op := p.previousScannedToken()
-		right, err := p.parseFactorExpression()
+		if op.Tok == token.PIPE {
+			expr, err = p.buildPipeExpression(expr, op.Pos)
 
-		if err != nil {
-			return nil, err
-		}
+			if err != nil {
+				return nil, err
+			}
 
-		expr = &ast.BinaryExpression{
-			Left:  expr,
-			Op:    op.Tok,
-			OpPos: op.Pos,
-			Right: right,
+			continue
 		}
-	}
-	return expr, nil
-}
-
-// *, /, %
-func (p *Parser) parseFactorExpression() (ast.Expression, error) {
-	expr, err := p.parseUnaryExpression()
-
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.QUO, token.STAR, token.PCT) {
 
-		op := p.previousScannedToken()
-		right, err := p.parseUnaryExpression()
+		right, err := p.parseBinaryOperation(opPrecedence + 1)
 
 		if err != nil {
 			return nil, err
@@ -328,8 +108,6 @@ func (p *Parser) parseFactorExpression() (ast.Expression, error) {
 			Right: right,
 		}
 	}
-
-	return expr, nil
 }
 
 // Unary -, *, !, &
@@ -410,6 +188,25 @@ func (p *Parser) buildCallExpression(target ast.Expression) (*ast.CallExpression
 	return expr, nil
 }
 
+// x |> f(y), the piped value is passed as the first argument of the call
+func (p *Parser) buildPipeExpression(value ast.Expression, pipe token.TokenPosition) (ast.Expression, error) {
+	target, err := p.parseFunctionCallExpression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	call, ok := target.(*ast.CallExpression)
+
+	if !ok {
+		return nil, p.errorAt("expected function call after `|>`", target.Range())
+	}
+
+	call.PipePos = pipe
+	call.Arguments = append([]*ast.CallArgument{{Value: value}}, call.Arguments...)
+	return call, nil
+}
+
 func (p *Parser) parseCallArgument() (*ast.CallArgument, error) {
 
 	// foo(bar: 10) | foo(10)
@@ -457,8 +254,8 @@ func (p *Parser) parseCompositeLiteral() (ast.Expression, error) {
 		return nil, err
 	}
 
-	// Check if this is possibly a struct initialization
-	if ast.IsTypeNode(expr) {
+	// Check if this is possibly a struct initialization, the brace following the header of a loop or conditional opens its body
+	if ast.IsTypeNode(expr) && !p.inHeader {
 		anchor := p.cursor
 		// Parse Body
 		body, err := p.parseCompositeLiteralBody()
@@ -516,6 +313,17 @@ func (p *Parser) parseFieldAccessExpression() (ast.Expression, error) {
 	for p.match(token.PERIOD) {
 		dotPos := p.previousScannedToken().Pos
 
+		// Tuple Elements, `t.0`
+		if p.currentMatches(token.INTEGER) || p.currentMatches(token.FLOAT) {
+			expr, err = p.parseTupleIndices(expr, dotPos)
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
 		property, err := p.parseIndexExpression()
 
 		if err != nil {
@@ -532,6 +340,69 @@ func (p *Parser) parseFieldAccessExpression() (ast.Expression, error) {
 	return expr, nil
 }
 
+/*
+This parses the index of a tuple element following a period, the indices of nested tuples are scanned as a single float
+
+# Example
+
+`pair.0`, `nested.1.0`
+*/
+func (p *Parser) parseTupleIndices(target ast.Expression, dotPos token.TokenPosition) (ast.Expression, error) {
+	tok := p.currentScannedToken()
+
+	for i, part := range strings.Split(tok.Lit, ".") {
+		v, err := strconv.ParseUint(part, 10, 32)
+
+		if err != nil || (len(part) > 1 && part[0] == '0') {
+			return nil, p.error(fmt.Sprintf("invalid tuple index `%s`", tok.Lit))
+		}
+
+		// the nested indices of a float share its position
+		if i != 0 {
+			dotPos = tok.Pos
+		}
+
+		target = &ast.FieldAccessExpression{
+			Target: target,
+			Field: &ast.IntegerLiteral{
+				Pos:   tok.Pos,
+				Value: v,
+			},
+			DotPos: dotPos,
+		}
+	}
+
+	p.next()
+	return target, nil
+}
+
+// parses the remaining elements of a tuple literal, the first element has been parsed
+func (p *Parser) parseTupleLiteral(start token.TokenPosition, first ast.Expression) (ast.Expression, error) {
+	elements := []ast.Expression{first}
+
+	for p.match(token.COMMA) {
+		expr, err := p.parseExpression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, expr)
+	}
+
+	end, err := p.expect(token.RPAREN)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.TupleLiteral{
+		LParenPos: start,
+		Elements:  elements,
+		RParenPos: end.Pos,
+	}, nil
+}
+
 func (p *Parser) parseIndexExpression() (ast.Expression, error) {
 	expr, err := p.parsePrimaryExpression()
 
@@ -593,67 +464,77 @@ func (p *Parser) parsePrimaryExpression() (ast.Expression, error) {
 		p.next()
 
 	case token.INTEGER:
-		v, err := strconv.ParseInt(p.currentScannedToken().Lit, 0, 64)
+		v, err := p.parseIntegerLiteral()
 
 		if err != nil {
 			return nil, err
 		}
-		expr = &ast.IntegerLiteral{
-			Value: v,
-			Pos:   p.currentScannedToken().Pos,
-		}
+		expr = v
 		p.next()
 
 	case token.FLOAT:
-		v, err := strconv.ParseFloat(p.currentScannedToken().Lit, 64)
+		v, err := p.parseFloatLiteral()
 
 		if err != nil {
 			return nil, err
 		}
 
-		expr = &ast.FloatLiteral{
-			Value: v,
-			Pos:   p.currentScannedToken().Pos,
-		}
+		expr = v
 		p.next()
 
 	case token.STRING:
-		expr = &ast.StringLiteral{
-			Value: p.currentScannedToken().Lit,
-			Pos:   p.currentScannedToken().Pos,
+		v, err := p.parseStringLiteral(p.currentScannedToken())
+
+		if err != nil {
+			return nil, err
 		}
+
+		expr = v
 		p.next()
+	case token.STRING_HEAD:
+		return p.parseInterpolatedStringLiteral()
 	case token.CHAR:
-		l := p.currentScannedToken().Lit
-		n := len(l)
-		code, _, _, err := strconv.UnquoteChar(l[1:n-1], '\'')
+		v, err := p.parseCharLiteral()
 
 		if err != nil {
 			return nil, err
 		}
 
-		expr = &ast.CharLiteral{
-			Value: int64(code),
-			Pos:   p.currentScannedToken().Pos,
-		}
-
+		expr = v
 		p.next()
 
 	case token.IDENTIFIER:
 		return p.parseIdentifierWithoutAnnotation()
 
+	case token.IF:
+		return p.parseIfExpression()
+
+	case token.FUNC:
+		return p.parseClosureExpression()
+
 	case token.LPAREN:
 		start, err := p.expect(token.LPAREN)
 
 		if err != nil {
 			return nil, err
 		}
+
+		// composite literals are unambiguous within parentheses
+		inHeader := p.inHeader
+		p.inHeader = false
+		defer func() { p.inHeader = inHeader }()
+
 		expr, err := p.parseExpression()
 
 		if err != nil {
 			return nil, err
 		}
 
+		// Tuple, `(a, b)`
+		if p.currentMatches(token.COMMA) {
+			return p.parseTupleLiteral(start.Pos, expr)
+		}
+
 		end, err := p.expect(token.RPAREN)
 
 		if err != nil {
@@ -735,6 +616,7 @@ func (p *Parser) parseFunctionExpression(requiresBody bool) (*ast.FunctionExpres
 	if len(p.modifiers) != 0 {
 		mods = p.consumeModifiers()
 	}
+	doc := p.leadingDoc()
 	start, err := p.expect(token.FUNC) // Expect current to be `func`, consume
 
 	if err != nil {
@@ -764,6 +646,8 @@ func (p *Parser) parseFunctionExpression(requiresBody bool) (*ast.FunctionExpres
 		return nil, err
 	}
 
+	rParen := p.previousScannedToken()
+
 	if len(params) > 99 {
 		return nil, p.error("too many parameters, maximum of 99")
 	}
@@ -802,6 +686,8 @@ func (p *Parser) parseFunctionExpression(requiresBody bool) (*ast.FunctionExpres
 		Parameters:    params,
 		ReturnType:    retType,
 		GenericParams: genParams,
+		RParenPos:     rParen.Pos,
+		Doc:           doc,
 	}
 
 	if mods != nil {
@@ -814,9 +700,69 @@ func (p *Parser) parseFunctionExpression(requiresBody bool) (*ast.FunctionExpres
 	return fn, nil
 }
 
+/*
+This parses an anonymous function literal, its parameters are unlabeled
+
+# Example
+
+`let add = fn (x: int, y: int) -> int { return x + y; }`
+*/
+func (p *Parser) parseClosureExpression() (*ast.FunctionExpression, error) {
+	start, err := p.expect(token.FUNC)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.currentMatches(token.LPAREN) {
+		return nil, p.error("expected `(`")
+	}
+
+	// Parameters
+	params, err := p.parseFunctionParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	rParen := p.previousScannedToken()
+
+	if len(params) > 99 {
+		return nil, p.error("too many parameters, maximum of 99")
+	}
+
+	for _, param := range params {
+		if param.Label != param.Name {
+			return nil, p.errorAt("closure parameters cannot be labeled", param.Label.Range())
+		}
+	}
+
+	// Return Type
+	retType, err := p.parseFunctionReturnType()
+	if err != nil {
+		return nil, err
+	}
+
+	// Body, the brace opening a closure is never the body of an enclosing header
+	inHeader := p.inHeader
+	p.inHeader = false
+	body, err := p.parseFunctionBody()
+	p.inHeader = inHeader
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.FunctionExpression{
+		KeyWPos:    start.Pos,
+		Body:       body,
+		Parameters: params,
+		ReturnType: retType,
+		RParenPos:  rParen.Pos,
+	}, nil
+}
+
 func (p *Parser) parseFunctionBody() (*ast.BlockStatement, error) {
 	// Opening
-	_, err := p.expect(token.LBRACE)
+	start, err := p.expect(token.LBRACE)
 
 	if err != nil {
 		return nil, err
@@ -828,14 +774,16 @@ func (p *Parser) parseFunctionBody() (*ast.BlockStatement, error) {
 	}
 
 	// Closing
-	_, err = p.expect(token.RBRACE)
+	end, err := p.expect(token.RBRACE)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &ast.BlockStatement{
+		LBrackPos:  start.Pos,
 		Statements: statements,
+		RBrackPos:  end.Pos,
 	}, nil
 
 }
@@ -1165,3 +1113,95 @@ exit:
 		RBracePos: rBrace.Pos,
 	}, nil
 }
+
+func (p *Parser) parseIfExpression() (*ast.IfExpression, error) {
+	/**
+	  let x = if a { 1 } else if b { 2 } else { 3 };
+	*/
+	start, err := p.expect(token.IF)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inHeader := p.inHeader
+	defer func() {
+		p.inHeader = inHeader
+	}()
+
+	// 1 - Condition
+	p.inHeader = true
+	condition, err := p.parseExpression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.inHeader = false
+
+	// 2 - Action
+	_, err = p.expect(token.LBRACE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := p.parseExpression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(token.RBRACE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &ast.IfExpression{
+		KeyWPos:   start.Pos,
+		Condition: condition,
+		Action:    action,
+	}
+
+	// 3 - Alternative, required as the expression must yield a value
+	_, err = p.expect(token.ELSE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.currentMatches(token.IF) {
+		alt, err := p.parseIfExpression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr.Alternative = alt
+		expr.RBracePos = alt.RBracePos
+		return expr, nil
+	}
+
+	_, err = p.expect(token.LBRACE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	alt, err := p.parseExpression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := p.expect(token.RBRACE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expr.Alternative = alt
+	expr.RBracePos = end.Pos
+	return expr, nil
+}
@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/token"
+	"github.com/mantton/calypso/internal/calypso/types"
+)
+
+// parses the current INTEGER token, integers suffixed with a floating point type are parsed as floats
+func (p *Parser) parseIntegerLiteral() (ast.Expression, error) {
+	tok := p.currentScannedToken()
+	digits, suffix := splitNumericSuffix(tok.Lit)
+
+	t := types.LookUpSuffix(suffix)
+	if types.IsFloatingPoint(t) {
+		return p.parseFloatLiteral()
+	}
+
+	v, err := strconv.ParseUint(strings.ReplaceAll(digits, "_", ""), 0, 64)
+
+	if errors.Is(err, strconv.ErrRange) {
+		return nil, p.error(fmt.Sprintf("integer literal `%s` is too large", tok.Lit))
+	}
+
+	if err != nil {
+		return nil, p.error(fmt.Sprintf("invalid integer literal `%s`", tok.Lit))
+	}
+
+	// unsuffixed literals must fit the largest signed integer
+	typ, max := "i64", uint64(math.MaxInt64)
+
+	if suffix != "" {
+		bits, ok := types.SuffixWidth(suffix)
+		if !ok || !types.IsInteger(t) {
+			return nil, p.error(fmt.Sprintf("invalid suffix `%s` on integer literal", suffix))
+		}
+
+		typ, max = suffix, uint64(1)<<bits-1
+		if !types.IsUnsigned(t) {
+			max = uint64(1)<<(bits-1) - 1
+		}
+	}
+
+	if v > max {
+		return nil, p.error(fmt.Sprintf("integer literal `%s` overflows %s", tok.Lit, typ))
+	}
+
+	return &ast.IntegerLiteral{
+		Value:  v,
+		Pos:    tok.Pos,
+		Suffix: suffix,
+	}, nil
+}
+
+// parses the current FLOAT or float suffixed INTEGER token
+func (p *Parser) parseFloatLiteral() (ast.Expression, error) {
+	tok := p.currentScannedToken()
+	digits, suffix := splitNumericSuffix(tok.Lit)
+
+	bits := 64
+	if suffix != "" {
+		b, ok := types.SuffixWidth(suffix)
+		if !ok || !types.IsFloatingPoint(types.LookUpSuffix(suffix)) {
+			return nil, p.error(fmt.Sprintf("invalid suffix `%s` on float literal", suffix))
+		}
+		bits = b
+	}
+
+	v, err := strconv.ParseFloat(strings.ReplaceAll(digits, "_", ""), bits)
+
+	if errors.Is(err, strconv.ErrRange) {
+		typ := "double"
+		if bits == 32 {
+			typ = "float"
+		}
+		return nil, p.error(fmt.Sprintf("float literal `%s` overflows %s", tok.Lit, typ))
+	}
+
+	if err != nil {
+		return nil, p.error(fmt.Sprintf("invalid float literal `%s`", tok.Lit))
+	}
+
+	return &ast.FloatLiteral{
+		Value:  v,
+		Pos:    tok.Pos,
+		Suffix: suffix,
+	}, nil
+}
+
+// builds a string literal from a STRING token, whose literal is the source between the quotes
+func (p *Parser) parseStringLiteral(tok token.ScannedToken) (*ast.StringLiteral, error) {
+	v, err := unquote(tok.Lit, '"')
+
+	if err != nil {
+		return nil, p.error(fmt.Sprintf("invalid string literal \"%s\"", tok.Lit))
+	}
+
+	return &ast.StringLiteral{
+		Value: v,
+		Pos:   tok.Pos,
+	}, nil
+}
+
+/*
+Parses an interpolated string, starting at its STRING_HEAD token
+
+Each segment of the string is followed by an interpolated expression, up to the STRING_TAIL token. Empty segments are dropped.
+*/
+func (p *Parser) parseInterpolatedStringLiteral() (*ast.InterpolatedStringLiteral, error) {
+	tok := p.currentScannedToken()
+	lit := &ast.InterpolatedStringLiteral{
+		Start: tok.Pos,
+	}
+
+	for {
+		segment, err := p.parseStringLiteral(tok)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if segment.Value != "" {
+			lit.Parts = append(lit.Parts, segment)
+		}
+
+		p.next()
+
+		if tok.Tok == token.STRING_TAIL {
+			lit.End = tok.Pos
+			return lit, nil
+		}
+
+		expr, err := p.parseExpression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		lit.Parts = append(lit.Parts, expr)
+		tok = p.currentScannedToken()
+
+		if tok.Tok != token.STRING_MIDDLE && tok.Tok != token.STRING_TAIL {
+			return nil, p.error("expected `)` to close string interpolation")
+		}
+	}
+}
+
+// parses the current CHAR token, whose literal includes its quotes
+func (p *Parser) parseCharLiteral() (*ast.CharLiteral, error) {
+	tok := p.currentScannedToken()
+	body := strings.TrimSuffix(strings.TrimPrefix(tok.Lit, "'"), "'")
+
+	v, _, tail, err := strconv.UnquoteChar(body, '\'')
+
+	if err != nil || tail != "" {
+		return nil, p.error(fmt.Sprintf("invalid char literal %s", tok.Lit))
+	}
+
+	return &ast.CharLiteral{
+		Value: int64(v),
+		Pos:   tok.Pos,
+	}, nil
+}
+
+/*
+Decodes the escape sequences of a literal
+
+Unicode escapes (`\u`, `\U`) are encoded as UTF-8, byte escapes (`\x`, octal) are written as single bytes.
+*/
+func unquote(s string, quote byte) (string, error) {
+	b := &strings.Builder{}
+
+	for len(s) > 0 {
+		v, multibyte, tail, err := strconv.UnquoteChar(s, quote)
+
+		if err != nil {
+			return "", err
+		}
+
+		if v < utf8.RuneSelf || !multibyte {
+			b.WriteByte(byte(v))
+		} else {
+			b.WriteRune(v)
+		}
+
+		s = tail
+	}
+
+	return b.String(), nil
+}
+
+// splits a numeric literal scanned by the lexer into its digits & type suffix
+func splitNumericSuffix(lit string) (string, string) {
+	i := 0
+	hex := false
+
+	if len(lit) > 1 && lit[0] == '0' {
+		switch lit[1] {
+		case 'x', 'X':
+			i, hex = 2, true
+		case 'o', 'O', 'b', 'B':
+			i = 2
+		}
+	}
+
+	for ; i < len(lit); i++ {
+		c := lit[i]
+
+		switch {
+		case c == '_' || '0' <= c && c <= '9':
+		case hex && ('a' <= c && c <= 'f' || 'A' <= c && c <= 'F'):
+		case !hex && c == '.':
+		case !hex && (c == 'e' || c == 'E'):
+			if i+1 < len(lit) && (lit[i+1] == '+' || lit[i+1] == '-') {
+				i++
+			}
+		default:
+			return lit[:i], lit[i:]
+		}
+	}
+
+	return lit, ""
+}
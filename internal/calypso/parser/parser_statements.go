@@ -18,7 +18,12 @@ func (p *Parser) parseStatement() (ast.Statement, error) {
 		return p.parseReturnStatement()
 	case token.WHILE:
 		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
 	case token.IDENTIFIER:
+		if next, ok := p.peakAheadScannedToken(); ok && next.Tok == token.COLON {
+			return p.parseLabeledStatement()
+		}
 		return p.parseExpressionStatement()
 	case token.FUNC:
 		fn, err := p.parseFunctionExpression(false)
@@ -36,6 +41,8 @@ func (p *Parser) parseStatement() (ast.Statement, error) {
 		return p.parseSwitchStatement()
 	case token.BREAK:
 		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
 	case token.TYPE:
 		return p.parseTypeStatement()
 	case token.STAR:
@@ -55,11 +62,22 @@ func (p *Parser) parseVariableStatement() (*ast.VariableStatement, error) {
 	let x = `expr`;
 	const y = `expr`;
 	const z :int = `expr`;
+	let (a, b) = `expr`;
 	*/
 	isConst := p.current() == token.CONST
+	doc := p.leadingDoc()
 	start := p.currentScannedToken().Pos
 	p.next() // Move to next token
-	ident, err := p.parseIdentifierWithOptionalAnnotation()
+
+	var ident *ast.IdentifierExpression
+	var elements []*ast.IdentifierExpression
+
+	if p.currentMatches(token.LPAREN) {
+		elements, err = p.parseDestructuredIdentifiers()
+	} else {
+		ident, err = p.parseIdentifierWithOptionalAnnotation()
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -84,13 +102,49 @@ func (p *Parser) parseVariableStatement() (*ast.VariableStatement, error) {
 	return &ast.VariableStatement{
 		KeyWPos:    start,
 		Identifier: ident,
+		Elements:   elements,
 		Value:      expr,
 		IsConstant: isConst,
 		Visibility: vis,
+		Doc:        doc,
 	}, nil
 
 }
 
+// parses the names bound by destructuring a tuple, `(a, b: int)`
+func (p *Parser) parseDestructuredIdentifiers() ([]*ast.IdentifierExpression, error) {
+	start, err := p.expect(token.LPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	idents := []*ast.IdentifierExpression{}
+
+	for {
+		ident, err := p.parseIdentifierWithOptionalAnnotation()
+		if err != nil {
+			return nil, err
+		}
+
+		idents = append(idents, ident)
+
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+
+	end, err := p.expect(token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(idents) < 2 {
+		return nil, p.errorAt("tuples have at least two elements", token.SyntaxRange{Start: start.Pos, End: end.Pos})
+	}
+
+	return idents, nil
+}
+
 func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	/**
 	   {
@@ -127,10 +181,12 @@ func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
 
 func (p *Parser) parseIfStatement() (ast.Statement, error) {
 	/**
-	  if true {
-		return false;
+	  if (a) {
+		return 1;
+	  } else if (b) {
+		return 2;
 	  } else {
-		return true;
+		return 3;
 	  }
 	*/
 
@@ -173,7 +229,13 @@ func (p *Parser) parseIfStatement() (ast.Statement, error) {
 
 	if p.currentMatches(token.ELSE) {
 		p.next()
-		alt, err := p.parseBlockStatement()
+
+		var alt ast.Statement
+		if p.currentMatches(token.IF) {
+			alt, err = p.parseIfStatement()
+		} else {
+			alt, err = p.parseBlockStatement()
+		}
 
 		if err != nil {
 			return nil, err
@@ -259,6 +321,132 @@ func (p *Parser) parseWhileStatement() (ast.Statement, error) {
 	return stmt, nil
 }
 
+func (p *Parser) parseForStatement() (ast.Statement, error) {
+	/**
+	  for let i = 0; i < 10; i += 1 {
+		print(i);
+	  }
+
+	  for x in collection {
+		print(x);
+	  }
+	*/
+	start, err := p.expect(token.FOR)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// braces within the header open the body of the loop rather than a composite literal
+	inHeader := p.inHeader
+	defer func() {
+		p.inHeader = inHeader
+	}()
+
+	p.inHeader = true
+
+	if next, ok := p.peakAheadScannedToken(); ok && p.currentMatches(token.IDENTIFIER) && next.Tok == token.IN {
+		return p.parseForInStatement(start.Pos)
+	}
+
+	stmt := &ast.ForStatement{
+		KeyWPos: start.Pos,
+	}
+
+	// 1 - Init, consumes the trailing semicolon
+	switch p.current() {
+	case token.SEMICOLON:
+		p.next()
+	case token.LET:
+		stmt.Init, err = p.parseVariableStatement()
+	default:
+		stmt.Init, err = p.parseExpressionStatement()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 - Condition
+	if !p.currentMatches(token.SEMICOLON) {
+		stmt.Condition, err = p.parseExpression()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.expect(token.SEMICOLON)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 3 - Post
+	if !p.currentMatches(token.LBRACE) {
+		stmt.Post, err = p.parseExpression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch stmt.Post.(type) {
+		case *ast.AssignmentExpression, *ast.CallExpression, *ast.ShorthandAssignmentExpression:
+		default:
+			return nil, p.error("expected statement, not expression")
+		}
+	}
+
+	// 4 - Action Block
+	p.inHeader = false
+	block, err := p.parseBlockStatement()
+
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Action = block
+	return stmt, nil
+}
+
+func (p *Parser) parseForInStatement(start token.TokenPosition) (ast.Statement, error) {
+	// 1 - Element
+	element, err := p.parseIdentifierWithoutAnnotation()
+
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := p.expect(token.IN)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 2 - Collection
+	collection, err := p.parseExpression()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 3 - Action Block
+	p.inHeader = false
+	block, err := p.parseBlockStatement()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ForInStatement{
+		KeyWPos:    start,
+		Element:    element,
+		InPos:      in.Pos,
+		Collection: collection,
+		Action:     block,
+	}, nil
+}
+
 func (p *Parser) parseExpressionStatement() (ast.Statement, error) {
 
 	expr, err := p.parseExpression()
@@ -290,6 +478,7 @@ func (p *Parser) parseStructStatement() (*ast.StructStatement, error) {
 		p.errors.Add(err)
 	}
 
+	doc := p.leadingDoc()
 	keyw, err := p.expect(token.STRUCT)
 
 	if err != nil {
@@ -320,6 +509,7 @@ func (p *Parser) parseStructStatement() (*ast.StructStatement, error) {
 
 	for p.current() != token.RBRACE {
 		vis := ast.PRIVATE
+		doc := p.currentScannedToken().Doc
 
 		if p.match(token.PUB) {
 			vis = ast.PUBLIC
@@ -338,6 +528,7 @@ func (p *Parser) parseStructStatement() (*ast.StructStatement, error) {
 		sf := &ast.StructField{
 			Identifier: v,
 			Visibility: vis,
+			Doc:        doc,
 		}
 		properties = append(properties, sf)
 
@@ -361,6 +552,7 @@ func (p *Parser) parseStructStatement() (*ast.StructStatement, error) {
 		RBracePos:     rBrace.Pos,
 		Fields:        properties,
 		Visibility:    vis,
+		Doc:           doc,
 	}, nil
 }
 
@@ -371,6 +563,7 @@ func (p *Parser) parseEnumStatement() (*ast.EnumStatement, error) {
 		p.errors.Add(err)
 	}
 	// Keyword
+	doc := p.leadingDoc()
 	kw, err := p.expect(token.ENUM)
 
 	if err != nil {
@@ -403,9 +596,11 @@ func (p *Parser) parseEnumStatement() (*ast.EnumStatement, error) {
 		GenericParams: genericParams,
 		LBracePos:     lbrace.Pos,
 		Visibility:    vis,
+		Doc:           doc,
 	}
 
 	for p.current() != token.RBRACE {
+		doc := p.currentScannedToken().Doc
 
 		ident, err := p.parseIdentifierWithoutAnnotation()
 		if err != nil {
@@ -445,6 +640,7 @@ func (p *Parser) parseEnumStatement() (*ast.EnumStatement, error) {
 			Identifier:   ident,
 			Discriminant: discriminator,
 			Fields:       fields,
+			Doc:          doc,
 		}
 
 		stmt.Variants = append(stmt.Variants, variant)
@@ -497,16 +693,6 @@ func (p *Parser) parseFieldList() ([]ast.TypeExpression, error) {
 }
 
 func (p *Parser) parseSwitchStatement() (*ast.SwitchStatement, error) {
-	prevState := p.inSwitch
-	defer func() {
-		p.inSwitch = prevState
-	}()
-
-	p.inSwitch = true
-	defer func() {
-		p.inSwitch = false
-	}()
-
 	// 1 - Keyword
 	kw, err := p.expect(token.SWITCH)
 
@@ -693,25 +879,105 @@ func (p *Parser) parseSwitchCase(comp ast.Expression) (*ast.SwitchCaseExpression
 }
 
 func (p *Parser) parseBreakStatement() (*ast.BreakStatement, error) {
+	kw, err := p.expect(token.BREAK)
 
-	if !p.inSwitch {
-		return nil, p.error("cannot break outside switch statement")
+	if err != nil {
+		return nil, err
 	}
 
-	kw, err := p.expect(token.BREAK)
+	label, err := p.parseJumpLabel()
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = p.expect(token.SEMICOLON)
+	return &ast.BreakStatement{
+		KeyWPos: kw.Pos,
+		Label:   label,
+	}, nil
+}
+
+func (p *Parser) parseContinueStatement() (*ast.ContinueStatement, error) {
+	kw, err := p.expect(token.CONTINUE)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &ast.BreakStatement{
+	label, err := p.parseJumpLabel()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ContinueStatement{
 		KeyWPos: kw.Pos,
+		Label:   label,
+	}, nil
+}
+
+// parses the optional label of a `break` or `continue` statement & the trailing semicolon
+func (p *Parser) parseJumpLabel() (*ast.IdentifierExpression, error) {
+	var label *ast.IdentifierExpression
+	var err error
+
+	if p.currentMatches(token.IDENTIFIER) {
+		label, err = p.parseIdentifierWithoutAnnotation()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.expect(token.SEMICOLON)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func (p *Parser) parseLabeledStatement() (*ast.LabeledStatement, error) {
+	/**
+	  outer: for x in xs {
+		for y in ys {
+			continue outer;
+		}
+	  }
+	*/
+	label, err := p.parseIdentifierWithoutAnnotation()
+
+	if err != nil {
+		return nil, err
+	}
+
+	colon, err := p.expect(token.COLON)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stmt ast.Statement
+	switch p.current() {
+	case token.WHILE:
+		stmt, err = p.parseWhileStatement()
+	case token.FOR:
+		stmt, err = p.parseForStatement()
+	case token.SWITCH:
+		stmt, err = p.parseSwitchStatement()
+	default:
+		return nil, p.error("expected loop or switch statement after label")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.LabeledStatement{
+		Label:    label,
+		ColonPos: colon.Pos,
+		Stmt:     stmt,
 	}, nil
 }
 
@@ -723,6 +989,7 @@ func (p *Parser) parseTypeStatement() (*ast.TypeStatement, error) {
 	}
 
 	// Consume Keyword
+	doc := p.leadingDoc()
 	kw, err := p.expect(token.TYPE)
 
 	if err != nil {
@@ -774,6 +1041,7 @@ func (p *Parser) parseTypeStatement() (*ast.TypeStatement, error) {
 		Value:         value,
 		Identifier:    ident,
 		Visibility:    vis,
+		Doc:           doc,
 	}, nil
 }
 
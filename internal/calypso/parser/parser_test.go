@@ -1,10 +1,14 @@
 package parser
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lexer"
+	"github.com/mantton/calypso/internal/calypso/token"
 )
 
 func scan(input string) *Parser {
@@ -79,3 +83,650 @@ func TestEnumSpecializtion(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDocComments(t *testing.T) {
+	input := `
+		/// A point on a plane.
+		///
+		/// Points are immutable.
+		pub struct Point {
+			/// The horizontal position.
+			x: int;
+			// not a doc comment
+			y: int;
+		}
+
+		/* ignored /* nested */ */
+		enum Shape {
+			/// Has no corners.
+			Circle,
+			Square,
+		}
+
+		//// not a doc comment either
+		/// Adds two numbers.
+		pub fn add(a: int, b: int) -> int {
+			return a + b;
+		}
+	`
+
+	p := scan(input)
+	file, err := p.TestParse()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	point := file.Nodes.Structs[0]
+	if point.Doc != "A point on a plane.\n\nPoints are immutable." {
+		t.Errorf("unexpected struct doc %q", point.Doc)
+	}
+
+	if point.Fields[0].Doc != "The horizontal position." || point.Fields[1].Doc != "" {
+		t.Errorf("unexpected field docs %q, %q", point.Fields[0].Doc, point.Fields[1].Doc)
+	}
+
+	shape := file.Nodes.Enums[0]
+	if shape.Doc != "" || shape.Variants[0].Doc != "Has no corners." || shape.Variants[1].Doc != "" {
+		t.Errorf("unexpected enum docs %q, %q, %q", shape.Doc, shape.Variants[0].Doc, shape.Variants[1].Doc)
+	}
+
+	add := file.Nodes.Functions[0].Func
+	if add.Doc != "Adds two numbers." {
+		t.Errorf("unexpected function doc %q", add.Doc)
+	}
+}
+
+func TestNumericLiterals(t *testing.T) {
+	tests := []struct {
+		input  string
+		value  any
+		suffix string
+	}{
+		{"0xFF", uint64(255), ""},
+		{"0o17", uint64(15), ""},
+		{"0b1010", uint64(10), ""},
+		{"1_000_000", uint64(1000000), ""},
+		{"255u8", uint64(255), "u8"},
+		{"0x7Fi8", uint64(127), "i8"},
+		{"65535u16", uint64(65535), "u16"},
+		{"0x7FFF_FFFFi32", uint64(math.MaxInt32), "i32"},
+		{"4294967295u32", uint64(math.MaxUint32), "u32"},
+		{"0xFFFF_FFFF_FFFF_FFFFu64", uint64(math.MaxUint64), "u64"},
+		{"1.5e-3", 1.5e-3, ""},
+		{"3.0f", 3.0, "f"},
+		{"2f", 2.0, "f"},
+		{"1_0.2_5d", 10.25, "d"},
+	}
+
+	for _, test := range tests {
+		p := scan("const A = " + test.input + ";")
+		file, err := p.TestParse()
+
+		if err != nil {
+			t.Errorf("unexpected error parsing %s: %s", test.input, err)
+			continue
+		}
+
+		var value any
+		var suffix string
+		switch v := file.Nodes.Constants[0].Stmt.Value.(type) {
+		case *ast.IntegerLiteral:
+			value, suffix = v.Value, v.Suffix
+		case *ast.FloatLiteral:
+			value, suffix = v.Value, v.Suffix
+		}
+
+		if value != test.value || suffix != test.suffix {
+			t.Errorf("expected %s to parse as %v%s, found %v%s", test.input, test.value, test.suffix, value, suffix)
+		}
+	}
+}
+
+func TestNumericLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"256u8", "integer literal `256u8` overflows u8"},
+		{"128i8", "integer literal `128i8` overflows i8"},
+		{"32768i16", "integer literal `32768i16` overflows i16"},
+		{"4294967296u32", "integer literal `4294967296u32` overflows u32"},
+		{"9223372036854775808", "overflows i64"},
+		{"99999999999999999999", "is too large"},
+		{"10u7", "invalid suffix `u7` on integer literal"},
+		{"1.5u8", "invalid suffix `u8` on float literal"},
+		{"1e39f", "float literal `1e39f` overflows float"},
+	}
+
+	for _, test := range tests {
+		_, errs := ParseString("module main;\nconst A = " + test.input + ";")
+
+		if len(errs) == 0 {
+			t.Errorf("expected error containing %q, parsed %s successfully", test.err, test.input)
+			continue
+		}
+
+		if !strings.Contains(errs.String(), test.err) {
+			t.Errorf("expected error containing %q, found %q", test.err, errs.String())
+		}
+	}
+}
+
+func TestEscapedLiterals(t *testing.T) {
+	tests := []struct {
+		input string
+		value any
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"tab\there"`, "tab\there"},
+		{`"quote \" & backslash \\"`, `quote " & backslash \`},
+		{`"\x41\101é\U0001F600"`, "AAé😀"},
+		{`"\xff"`, "\xff"},
+		{`"é"`, "é"},
+		{`'\n'`, int64('\n')},
+		{`'\''`, int64('\'')},
+		{`'\x7f'`, int64(0x7f)},
+		{`'é'`, int64('é')},
+		{`'世'`, int64('世')},
+		{`'😀'`, int64('😀')},
+	}
+
+	for _, test := range tests {
+		file, errs := ParseString("module main;\nconst A = " + test.input + ";")
+
+		if len(errs) != 0 {
+			t.Errorf("unexpected error parsing %s: %s", test.input, errs.String())
+			continue
+		}
+
+		var value any
+		switch v := file.Nodes.Constants[0].Stmt.Value.(type) {
+		case *ast.StringLiteral:
+			value = v.Value
+		case *ast.CharLiteral:
+			value = v.Value
+		}
+
+		if value != test.value {
+			t.Errorf("expected %s to decode to %q, found %q", test.input, test.value, value)
+		}
+	}
+}
+
+func TestInterpolatedStringLiteral(t *testing.T) {
+	file, errs := ParseString("module main;\nconst A = \"hello \\(name), you are \\(age + 1)\\n\";")
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	lit, ok := file.Nodes.Constants[0].Stmt.Value.(*ast.InterpolatedStringLiteral)
+	if !ok {
+		t.Fatalf("expected interpolated string literal, found %T", file.Nodes.Constants[0].Stmt.Value)
+	}
+
+	if len(lit.Parts) != 5 {
+		t.Fatalf("expected 5 parts, found %d", len(lit.Parts))
+	}
+
+	if _, ok := lit.Parts[1].(*ast.IdentifierExpression); !ok {
+		t.Errorf("expected identifier, found %T", lit.Parts[1])
+	}
+
+	if _, ok := lit.Parts[3].(*ast.BinaryExpression); !ok {
+		t.Errorf("expected binary expression, found %T", lit.Parts[3])
+	}
+
+	if s, ok := lit.Parts[4].(*ast.StringLiteral); !ok || s.Value != "\n" {
+		t.Errorf("expected the escaped tail to decode to a newline, found %s", lit.Parts[4])
+	}
+
+	_, errs = ParseString("module main;\nconst A = \"a \\(b c) d\";")
+	if len(errs) == 0 {
+		t.Errorf("expected an error for an unclosed interpolation")
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	input := `
+	module main;
+
+	fn main() {
+		for let i = 0; i < 10; i += 1 {
+			let p = Point { X: i };
+		}
+
+		for x in points {
+		}
+
+		for ; ; {
+		}
+	}
+	`
+
+	file, errs := ParseString(input)
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	stmts := file.Nodes.Functions[0].Func.Body.Statements
+	if len(stmts) != 3 {
+		t.Fatalf("expected 3 statements, found %d", len(stmts))
+	}
+
+	loop, ok := stmts[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("expected for statement, found %T", stmts[0])
+	}
+
+	if _, ok := loop.Init.(*ast.VariableStatement); !ok {
+		t.Errorf("expected variable statement as init, found %T", loop.Init)
+	}
+
+	if _, ok := loop.Post.(*ast.ShorthandAssignmentExpression); !ok {
+		t.Errorf("expected shorthand assignment as post, found %T", loop.Post)
+	}
+
+	if _, ok := loop.Action.Statements[0].(*ast.VariableStatement).Value.(*ast.CompositeLiteral); !ok {
+		t.Errorf("expected composite literals within the body of the loop")
+	}
+
+	rng, ok := stmts[1].(*ast.ForInStatement)
+	if !ok {
+		t.Fatalf("expected for in statement, found %T", stmts[1])
+	}
+
+	if rng.Element.Value != "x" {
+		t.Errorf("expected element x, found %s", rng.Element.Value)
+	}
+
+	// the brace following the collection opens the body
+	if _, ok := rng.Collection.(*ast.IdentifierExpression); !ok {
+		t.Errorf("expected identifier as collection, found %T", rng.Collection)
+	}
+
+	if loop := stmts[2].(*ast.ForStatement); loop.Init != nil || loop.Condition != nil || loop.Post != nil {
+		t.Errorf("expected an empty loop header")
+	}
+
+	_, errs = ParseString("module main;\nfn main() {\n\tfor let i = 0; i < 10; i {\n\t}\n}")
+	if len(errs) == 0 {
+		t.Errorf("expected an error for an expression as post statement")
+	}
+}
+
+func TestJumpStatements(t *testing.T) {
+	input := `
+	module main;
+
+	fn main() {
+		outer: for x in xs {
+			while (true) {
+				continue outer;
+			}
+			break;
+		}
+		continue;
+	}
+	`
+
+	file, errs := ParseString(input)
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	stmts := file.Nodes.Functions[0].Func.Body.Statements
+
+	labeled, ok := stmts[0].(*ast.LabeledStatement)
+	if !ok {
+		t.Fatalf("expected labeled statement, found %T", stmts[0])
+	}
+
+	if labeled.Label.Value != "outer" {
+		t.Errorf("expected label outer, found %s", labeled.Label.Value)
+	}
+
+	loop, ok := labeled.Stmt.(*ast.ForInStatement)
+	if !ok {
+		t.Fatalf("expected for in statement, found %T", labeled.Stmt)
+	}
+
+	inner := loop.Action.Statements[0].(*ast.WhileStatement)
+	if c, ok := inner.Action.Statements[0].(*ast.ContinueStatement); !ok || c.Label == nil || c.Label.Value != "outer" {
+		t.Errorf("expected continue outer, found %T", inner.Action.Statements[0])
+	}
+
+	if b, ok := loop.Action.Statements[1].(*ast.BreakStatement); !ok || b.Label != nil {
+		t.Errorf("expected unlabeled break, found %T", loop.Action.Statements[1])
+	}
+
+	// jumps outside loops are rejected by the typechecker
+	if _, ok := stmts[1].(*ast.ContinueStatement); !ok {
+		t.Errorf("expected continue, found %T", stmts[1])
+	}
+
+	_, errs = ParseString("module main;\nfn main() {\n\tlabel: let x = 10;\n}")
+	if len(errs) == 0 {
+		t.Errorf("expected an error for a label on a variable statement")
+	}
+}
+
+func TestIfChains(t *testing.T) {
+	input := `
+	module main;
+
+	fn main() {
+		if (a) {
+			return 1;
+		} else if (b) {
+			return 2;
+		} else {
+			return 3;
+		}
+
+		let x = if a { Point { X: 1 } } else if b { p } else { q };
+	}
+	`
+
+	file, errs := ParseString(input)
+
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	stmts := file.Nodes.Functions[0].Func.Body.Statements
+
+	stmt := stmts[0].(*ast.IfStatement)
+	alt, ok := stmt.Alternative.(*ast.IfStatement)
+	if !ok {
+		t.Fatalf("expected else if statement, found %T", stmt.Alternative)
+	}
+
+	if _, ok := alt.Alternative.(*ast.BlockStatement); !ok {
+		t.Errorf("expected else block, found %T", alt.Alternative)
+	}
+
+	expr, ok := stmts[1].(*ast.VariableStatement).Value.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("expected if expression, found %T", stmts[1].(*ast.VariableStatement).Value)
+	}
+
+	if _, ok := expr.Action.(*ast.CompositeLiteral); !ok {
+		t.Errorf("expected composite literal within the branch, found %T", expr.Action)
+	}
+
+	nested, ok := expr.Alternative.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("expected else if expression, found %T", expr.Alternative)
+	}
+
+	if expr.Range().End != nested.RBracePos {
+		t.Errorf("expected the chain to end at the brace of its final alternative")
+	}
+
+	_, errs = ParseString("module main;\nfn main() {\n\tlet x = if a { 1 };\n}")
+	if len(errs) == 0 {
+		t.Errorf("expected an error for an if expression without an alternative")
+	}
+}
+
+// renders the operands of binary expressions grouped by parentheses
+func grouping(e ast.Expression) string {
+	switch e := e.(type) {
+	case *ast.BinaryExpression:
+		return fmt.Sprintf("(%s %s %s)", grouping(e.Left), token.LookUp(e.Op), grouping(e.Right))
+	case *ast.IntegerLiteral:
+		return fmt.Sprint(e.Value)
+	case *ast.IdentifierExpression:
+		return e.Value
+	case *ast.CallExpression:
+		args := []string{}
+		for _, arg := range e.Arguments {
+			args = append(args, grouping(arg.Value))
+		}
+
+		return fmt.Sprintf("%s(%s)", grouping(e.Target), strings.Join(args, ", "))
+	}
+
+	return fmt.Sprintf("%T", e)
+}
+
+func TestBinaryPrecedence(t *testing.T) {
+	parse := func(expr string) string {
+		file, errs := ParseString(fmt.Sprintf("module main;\nconst A = %s;", expr))
+
+		if len(errs) != 0 {
+			t.Fatalf("unexpected error parsing %s: %s", expr, errs.String())
+		}
+
+		return grouping(file.Nodes.Constants[0].Stmt.Value)
+	}
+
+	for op, precedence := range token.BinaryPrecedent {
+		if op == token.PIPE {
+			continue
+		}
+
+		// every level is left-associative & chains
+		input := fmt.Sprintf("1 %s 2 %s 3 %s 4", op, op, op)
+		if s, expected := parse(input), fmt.Sprintf("(((1 %s 2) %s 3) %s 4)", op, op, op); s != expected {
+			t.Errorf("expected %s to parse as %s, found %s", input, expected, s)
+		}
+
+		for other, otherPrecedence := range token.BinaryPrecedent {
+			if otherPrecedence <= precedence || other == token.PIPE {
+				continue
+			}
+
+			// the tighter operator groups first, on either side
+			input := fmt.Sprintf("1 %s 2 %s 3", op, other)
+			if s, expected := parse(input), fmt.Sprintf("(1 %s (2 %s 3))", op, other); s != expected {
+				t.Errorf("expected %s to parse as %s, found %s", input, expected, s)
+			}
+
+			input = fmt.Sprintf("1 %s 2 %s 3", other, op)
+			if s, expected := parse(input), fmt.Sprintf("((1 %s 2) %s 3)", other, op); s != expected {
+				t.Errorf("expected %s to parse as %s, found %s", input, expected, s)
+			}
+		}
+	}
+
+	if s := parse("1 - 2 * 3 + 4 << 1 == 5 && 6 < 7 || 8 != 9"); s != "((((((1 - (2 * 3)) + 4) << 1) == 5) && (6 < 7)) || (8 != 9))" {
+		t.Errorf("unexpected grouping of mixed operators, found %s", s)
+	}
+}
+
+func TestPipeExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x |> f()", "f(x)"},
+		{"x |> f(1, 2)", "f(x, 1, 2)"},
+		{"x |> f() |> g(1)", "g(f(x), 1)"},
+		{"1 + 2 |> f() * 3", "(f((1 + 2)) * 3)"},
+		{"x |> f() == 1 && y |> g()", "((f(x) == 1) && g(y))"},
+		{"1 << x |> f()", "f((1 << x))"},
+		{"x |> f(y |> g())", "f(x, g(y))"},
+	}
+
+	for _, test := range tests {
+		file, errs := ParseString(fmt.Sprintf("module main;\nconst A = %s;", test.input))
+
+		if len(errs) != 0 {
+			t.Errorf("unexpected error parsing %s: %s", test.input, errs.String())
+			continue
+		}
+
+		call := file.Nodes.Constants[0].Stmt.Value
+		if s := grouping(call); s != test.expected {
+			t.Errorf("expected %s to parse as %s, found %s", test.input, test.expected, s)
+		}
+	}
+
+	_, errs := ParseString("module main;\nconst A = x |> f;")
+	if len(errs) == 0 || !strings.Contains(errs.String(), "expected function call after `|>`") {
+		t.Errorf("expected the right operand of a pipe to be a call, found %s", errs.String())
+	}
+
+	file, _ := ParseString("module main;\nconst A = abc |> f(1);")
+	if r := file.Nodes.Constants[0].Stmt.Value.Range(); r.Start.Start != 23 || r.End.Start != 33 {
+		t.Errorf("expected a piped call to span from its piped value, found [%d, %d]", r.Start.Start, r.End.Start)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	input := `module main;
+const A : fn(int, int) -> bool = fn (a: int, b: int) -> bool { return a < b; };
+const B : fn() = fn () {};
+const C : *fn(fn(int) -> int) = nil;
+`
+	file, errs := ParseString(input)
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	a := file.Nodes.Constants[0].Stmt
+	typ, ok := a.Identifier.AnnotatedType.(*ast.FunctionTypeExpression)
+	if !ok || len(typ.Arguments) != 2 || typ.ReturnType == nil {
+		t.Errorf("expected a function type of 2 arguments returning a value, found %T", a.Identifier.AnnotatedType)
+	}
+
+	fn, ok := a.Value.(*ast.FunctionExpression)
+	if !ok || fn.Identifier != nil || len(fn.Parameters) != 2 || fn.ReturnType == nil || len(fn.Body.Statements) != 1 {
+		t.Errorf("expected an anonymous function of 2 parameters, found %T", a.Value)
+	}
+
+	b := file.Nodes.Constants[1].Stmt
+	if typ, ok := b.Identifier.AnnotatedType.(*ast.FunctionTypeExpression); !ok || len(typ.Arguments) != 0 || typ.ReturnType != nil {
+		t.Errorf("expected a function type without arguments returning void, found %T", b.Identifier.AnnotatedType)
+	}
+
+	c := file.Nodes.Constants[2].Stmt
+	if ptr, ok := c.Identifier.AnnotatedType.(*ast.PointerTypeExpression); !ok {
+		t.Errorf("expected a pointer type, found %T", c.Identifier.AnnotatedType)
+	} else if _, ok := ptr.PointerTo.(*ast.FunctionTypeExpression); !ok {
+		t.Errorf("expected a pointer to a function type, found %T", ptr.PointerTo)
+	}
+
+	// closures are expressions, so they may be passed as arguments & open within the header of a loop
+	file, errs = ParseString("module main;\nfn main() { sort(xs, fn (a: int, b: int) -> bool { return a > b; }); for let i = 0; apply(fn (x: int) -> bool { return x < 10; }, i); i += 1 {} }")
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	_, errs = ParseString("module main;\nconst A = fn (with a: int) {};")
+	if len(errs) == 0 || !strings.Contains(errs.String(), "closure parameters cannot be labeled") {
+		t.Errorf("expected labeled closure parameters to be rejected, found %s", errs.String())
+	}
+}
+
+func TestTuples(t *testing.T) {
+	input := `module main;
+fn divide(_ a: int, by b: int) -> (int, bool) {
+	let (q, ok: bool) = (a / b, true);
+	return (q, ok);
+}
+fn main() {
+	const t = ((1, 2), 3);
+	const x = t.0.1 + t.1;
+	const y = (x);
+}
+`
+	file, errs := ParseString(input)
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	divide := file.Nodes.Functions[0].Func
+	if typ, ok := divide.ReturnType.(*ast.TupleTypeExpression); !ok || len(typ.Elements) != 2 {
+		t.Errorf("expected a tuple type of 2 elements, found %T", divide.ReturnType)
+	}
+
+	stmt := divide.Body.Statements[0].(*ast.VariableStatement)
+	if stmt.Identifier != nil || len(stmt.Elements) != 2 || stmt.Elements[1].AnnotatedType == nil {
+		t.Errorf("expected the declaration to destructure 2 elements, the second annotated")
+	}
+
+	if lit, ok := stmt.Value.(*ast.TupleLiteral); !ok || len(lit.Elements) != 2 {
+		t.Errorf("expected a tuple literal of 2 elements, found %T", stmt.Value)
+	}
+
+	// the indices of nested tuples are scanned as a float, `t.0.1`
+	body := file.Nodes.Functions[1].Func.Body.Statements
+	x := body[1].(*ast.VariableStatement).Value.(*ast.BinaryExpression)
+
+	outer, ok := x.Left.(*ast.FieldAccessExpression)
+	if !ok {
+		t.Fatalf("expected a field access, found %T", x.Left)
+	}
+
+	inner, ok := outer.Target.(*ast.FieldAccessExpression)
+	if !ok || inner.Field.(*ast.IntegerLiteral).Value != 0 || outer.Field.(*ast.IntegerLiteral).Value != 1 {
+		t.Errorf("expected `t.0.1` to access the second element of the first element")
+	}
+
+	if _, ok := body[2].(*ast.VariableStatement).Value.(*ast.GroupedExpression); !ok {
+		t.Errorf("expected a parenthesized expression without a comma to be grouped")
+	}
+
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"module main;\nfn main() { let (a) = b; }", "tuples have at least two elements"},
+		{"module main;\nfn f() -> (int) {}", "tuples have at least two elements"},
+		{"module main;\nfn main() { let a = t.01; }", "invalid tuple index `01`"},
+		{"module main;\nconst (a, b) = (1, 2);", "top level constants cannot be destructured"},
+	}
+
+	for _, test := range tests {
+		_, errs := ParseString(test.input)
+		if len(errs) == 0 || !strings.Contains(errs.String(), test.err) {
+			t.Errorf("%q: expected %q, found %s", test.input, test.err, errs.String())
+		}
+	}
+}
+
+func TestSyntaxTree(t *testing.T) {
+	inputs := []string{
+		"module main;\n\n/// doc\n/* block /* nested */ */ fn main() {\n\tlet café = \"hi \\(1 + 2) 😀\"; // trailing\n\treturn;\n}\n",
+		"  module main;\r\n\r\nconst A = 'é';\t\n\n\n",
+		"module main;\n\nfn broken( {",
+		"",
+	}
+
+	for _, input := range inputs {
+		tree, _ := ParseSyntaxTree(input)
+
+		if s := tree.String(); s != input {
+			t.Errorf("expected the tree to print its source\nexpected %q\nfound    %q", input, s)
+		}
+	}
+
+	tree, errs := ParseSyntaxTree(inputs[0])
+	if len(errs) != 0 {
+		t.Fatal(errs.String())
+	}
+
+	if len(tree.Children) != 5 || tree.Children[0].Token.Tok != token.MODULE || tree.Children[4].Token.Tok != token.EOF {
+		t.Fatalf("expected the module header, a function & EOF in the root, found %d children", len(tree.Children))
+	}
+
+	fn := tree.Children[3].Node
+	if _, ok := fn.AST.(*ast.FunctionDeclaration); !ok {
+		t.Fatalf("expected a function declaration, found %T", fn.AST)
+	}
+
+	if s := fn.String(); !strings.HasPrefix(s, "\n\n/// doc\n/* block /* nested */ */ fn main()") || !strings.HasSuffix(s, "return;\n}") {
+		t.Errorf("expected the function to span its tokens & their trivia, found %q", s)
+	}
+
+	tokens := tree.Tokens()
+	if tokens[len(tokens)-1].Trivia[0].Lit != "\n" {
+		t.Errorf("expected the trailing line break to be the trivia of EOF")
+	}
+}
@@ -25,6 +25,16 @@ func (p *Parser) parseTypeExpression() (ast.TypeExpression, error) {
 		if err != nil {
 			return nil, err
 		}
+	case token.FUNC:
+		typ, err = p.parseFunctionTypeExpression()
+		if err != nil {
+			return nil, err
+		}
+	case token.LPAREN:
+		typ, err = p.parseTupleTypeExpression()
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, p.error("expected type expression")
 	}
@@ -263,6 +273,115 @@ func (p *Parser) parseGenericParameterExpression() (*ast.GenericParameterExpress
 	}, nil
 }
 
+/*
+This parses a function type, the return type may be omitted for functions returning void
+
+# Example
+
+`let compare: fn(int, int) -> bool`
+*/
+func (p *Parser) parseFunctionTypeExpression() (*ast.FunctionTypeExpression, error) {
+	start, err := p.expect(token.FUNC)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(token.LPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []ast.TypeExpression{}
+
+	if !p.currentMatches(token.RPAREN) {
+		// First Argument
+		expr, err := p.parseTypeExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, expr)
+
+		// Check For Others
+		for p.match(token.COMMA) {
+			expr, err := p.parseTypeExpression()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, expr)
+		}
+	}
+
+	end, err := p.expect(token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 99 {
+		return nil, p.error("too many parameters, maximum of 99")
+	}
+
+	retType, err := p.parseFunctionReturnType()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.FunctionTypeExpression{
+		KeyWPos:    start.Pos,
+		Arguments:  args,
+		RParenPos:  end.Pos,
+		ReturnType: retType,
+	}, nil
+}
+
+/*
+This parses a tuple type of two or more elements
+
+# Example
+
+`fn divide(_ a: int, by b: int) -> (int, bool)`
+*/
+func (p *Parser) parseTupleTypeExpression() (*ast.TupleTypeExpression, error) {
+	start, err := p.expect(token.LPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := []ast.TypeExpression{}
+
+	expr, err := p.parseTypeExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	elements = append(elements, expr)
+
+	for p.match(token.COMMA) {
+		expr, err := p.parseTypeExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, expr)
+	}
+
+	end, err := p.expect(token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) < 2 {
+		return nil, p.errorAt("tuples have at least two elements", token.SyntaxRange{Start: start.Pos, End: end.Pos})
+	}
+
+	return &ast.TupleTypeExpression{
+		LParenPos: start.Pos,
+		Elements:  elements,
+		RParenPos: end.Pos,
+	}, nil
+}
+
 func (p *Parser) parsePointerTypeExpression() (*ast.PointerTypeExpression, error) {
 	pos, err := p.expect(token.STAR)
 	if err != nil {
@@ -56,6 +56,16 @@ func (p *Parser) expect(t token.Token) (token.ScannedToken, error) {
 	}
 }
 
+// the doc comment of the declaration at the current token, attached to the first of any modifiers preceding it
+func (p *Parser) leadingDoc() string {
+	idx := p.cursor
+	for idx > 0 && token.IsModifier(p.file.Tokens[idx-1].Tok) {
+		idx--
+	}
+
+	return p.file.Tokens[idx].Doc
+}
+
 func (p *Parser) next() {
 	if p.isAtEnd() {
 		return
@@ -27,6 +27,19 @@ func (r *resolver) addError(e error) {
 }
 
 func ParseAndResolve(path string) ([]*ast.Package, error) {
+	return resolve(func(r *resolver) {
+		r.ParsePackage(path, true)
+	})
+}
+
+// Parses & Resolves a package which has already been collected, for example a package synthesized from a set of files
+func ParseAndResolvePackage(pkg *fs.Package) ([]*ast.Package, error) {
+	return resolve(func(r *resolver) {
+		r.parsePackage(pkg, true)
+	})
+}
+
+func resolve(parseTarget func(*resolver)) ([]*ast.Package, error) {
 	// Collect paths
 	r := &resolver{
 		programModuleGraph:  simple.NewDirectedGraph(),
@@ -39,7 +52,7 @@ func ParseAndResolve(path string) ([]*ast.Package, error) {
 	r.ParsePackage(fs.GetSTDPath(), false)
 
 	// 2 - Parse Target Package
-	r.ParsePackage(path, true)
+	parseTarget(r)
 
 	// Perform Queued actions
 	for r.queuedActions.Length() != 0 {
@@ -86,11 +99,15 @@ func (r *resolver) ParsePackage(p string, entry bool) *ast.Package {
 		return nil
 	}
 
+	return r.parsePackage(pkg, entry)
+}
+
+func (r *resolver) parsePackage(pkg *fs.Package, entry bool) *ast.Package {
 	astPackage := ast.NewPackage(pkg)
 	r.programPackageGraph.AddNode(astPackage)
 
 	// store
-	if p == fs.GetSTDPath() {
+	if pkg.IsSTD() {
 		r.pacakges["std"] = astPackage
 	} else {
 		r.pacakges[astPackage.Key()] = astPackage
@@ -10,13 +10,26 @@ type ScannedToken struct {
 	Tok Token
 	Pos TokenPosition
 	Lit string
+	Doc string // text of the `///` comments preceding the token, one line per comment
+
+	Trivia []ScannedToken // the whitespace & comments preceding the token, retained when scanning with trivia
 }
 
+/*
+The position of a token in its source file
+
+Start & End index the runes of the source, StartByte & EndByte are the equivalent byte offsets.
+Columns count from 1, Offset in runes & UTF16Offset in the UTF-16 code units editors measure lines in.
+*/
 type TokenPosition struct {
 	Line   int
 	Offset int
 	Start  int
 	End    int
+
+	UTF16Offset int
+	StartByte   int
+	EndByte     int
 }
 
 type SyntaxRange struct {
@@ -33,12 +46,19 @@ const (
 	IGNORE
 	EOF
 
+	// * TRIVIA
+	WHITESPACE // spaces, tabs & line breaks
+	COMMENT    // `//`, `///` & `/* */` comments
+
 	// * LITERALS
 	lit_b // Literals Begin
 	IDENTIFIER
 	INTEGER
 	FLOAT
 	STRING
+	STRING_HEAD   // `"a \(`, the segment of an interpolated string before its first interpolation
+	STRING_MIDDLE // `) b \(`, a segment between two interpolations
+	STRING_TAIL   // `) c"`, the segment after the last interpolation
 	CHAR
 	lit_e // Literals End
 
@@ -117,12 +137,14 @@ const (
 	CONFORM
 	FOR
 	TO
+	IN
 	EXTERN
 	SWITCH
 	ENUM
 	CASE
 	DEFAULT
 	BREAK
+	CONTINUE
 	/// Modifiers
 	ASYNC
 	STATIC
@@ -159,12 +181,14 @@ var keywords = map[string]Token{
 	"conform":   CONFORM,
 	"for":       FOR,
 	"to":        TO,
+	"in":        IN,
 	"extern":    EXTERN,
 	"enum":      ENUM,
 	"switch":    SWITCH,
 	"case":      CASE,
 	"default":   DEFAULT,
 	"break":     BREAK,
+	"continue":  CONTINUE,
 	"pub":       PUB,
 	"static":    STATIC,
 	"mutating":  MUTATING,
@@ -189,7 +213,7 @@ func IsDeclaration(t Token) bool {
 
 func IsStatement(t Token) bool {
 	switch t {
-	case FUNC, LET, CONST, IF, RETURN, STRUCT, FOR, ENUM, SWITCH, BREAK, WHILE, TYPE:
+	case FUNC, LET, CONST, IF, RETURN, STRUCT, FOR, ENUM, SWITCH, BREAK, CONTINUE, WHILE, TYPE:
 		return true
 	}
 
@@ -214,8 +238,11 @@ func IsModifiable(t Token) bool {
 }
 
 var tokens = map[Token]string{
-	ILLEGAL: "ILLEGAL",
-	EOF:     "EOF",
+	ILLEGAL:    "ILLEGAL",
+	IGNORE:     "IGNORE",
+	WHITESPACE: "WHITESPACE",
+	COMMENT:    "COMMENT",
+	EOF:        "EOF",
 
 	PLUS:  "+",
 	MINUS: "-",
@@ -254,16 +281,19 @@ var tokens = map[Token]string{
 	LEQ: "<=",
 	GEQ: ">=",
 
-	LPAREN: "(",
-	LBRACE: "{",
-	COMMA:  ",",
-	PERIOD: ".",
+	LPAREN:   "(",
+	LBRACE:   "{",
+	LBRACKET: "[",
+	COMMA:    ",",
+	PERIOD:   ".",
 
 	RPAREN:    ")",
 	RBRACE:    "}",
+	RBRACKET:  "]",
 	SEMICOLON: ";",
 	COLON:     ":",
 	R_ARROW:   "->",
+	PIPE:      "|>",
 
 	IMPORT:    "import",
 	CONST:     "const",
@@ -280,18 +310,34 @@ var tokens = map[Token]string{
 	CONFORM:   "conform",
 	FOR:       "for",
 	TO:        "to",
+	IN:        "in",
 	SWITCH:    "switch",
+	CASE:      "case",
 	DEFAULT:   "default",
 	BREAK:     "break",
+	CONTINUE:  "continue",
+	TRUE:      "true",
+	FALSE:     "false",
+	NIL:       "nil",
+	VOID:      "void",
+	MODULE:    "module",
+	EXTERN:    "extern",
+	ENUM:      "enum",
 
 	PUB:      "public",
 	STATIC:   "static",
 	MUTATING: "mutating",
 	ASYNC:    "async",
 
-	IDENTIFIER: "IDENTIFIER",
-	STRING:     "STRING",
-	AS:         "as",
+	IDENTIFIER:    "IDENTIFIER",
+	INTEGER:       "INTEGER",
+	FLOAT:         "FLOAT",
+	STRING:        "STRING",
+	STRING_HEAD:   "STRING_HEAD",
+	STRING_MIDDLE: "STRING_MIDDLE",
+	STRING_TAIL:   "STRING_TAIL",
+	CHAR:          "CHAR",
+	AS:            "as",
 }
 
 var ModifierPrecedent = map[Token]int{
@@ -306,6 +352,37 @@ var ModifierPrecedent = map[Token]int{
 	PUB: 0,
 }
 
+// The precedence of binary operators, operators of higher precedence bind tighter. Tokens which are not binary operators have none
+var BinaryPrecedent = map[Token]int{
+	DOUBLE_BAR: 1,
+	DOUBLE_AMP: 2,
+
+	EQL: 3,
+	NEQ: 3,
+
+	L_CHEVRON: 4,
+	R_CHEVRON: 4,
+	LEQ:       4,
+	GEQ:       4,
+
+	// `x |> f(y)`, the right operand is a call rather than an operation
+	PIPE: 5,
+
+	BAR:   6,
+	CARET: 7,
+	AMP:   8,
+
+	BIT_SHIFT_LEFT:  9,
+	BIT_SHIFT_RIGHT: 9,
+
+	PLUS:  10,
+	MINUS: 10,
+
+	STAR: 11,
+	QUO:  11,
+	PCT:  11,
+}
+
 func LookUp(t Token) string {
 	v, ok := tokens[t]
 
@@ -43,5 +43,11 @@ func (c *Checker) validateAssignment(variable *types.Var, provided types.Type, n
 
 	variable.SetType(expected)
 	c.module.Table.SetNodeType(node, expected)
+
+	// the branches of an if expression take the type it is assigned to
+	if n, ok := node.(*ast.IfExpression); ok {
+		c.setIfExpressionType(n, expected)
+	}
+
 	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"errors"
 
 	"github.com/mantton/calypso/internal/calypso/ast"
+	"github.com/mantton/calypso/internal/calypso/fs"
 	"github.com/mantton/calypso/internal/calypso/lexer"
+	"github.com/mantton/calypso/internal/calypso/logging"
 	"github.com/mantton/calypso/internal/calypso/parser"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
@@ -14,15 +16,22 @@ type Checker struct {
 	depth  int
 	ctx    *NodeContext
 	file   *ast.File
+	log    *logging.Logger
 
 	module *types.Module
 	mp     *types.PackageMap
+
+	targets []ast.Statement         // the loops & switches enclosing the statement being checked, innermost last
+	labels  []*ast.LabeledStatement // the labeled statements enclosing the statement being checked, innermost last
+
+	closures map[*types.Scope]*types.Function // the scopes of closures, mapped to their function
 }
 
 func New(mod *ast.Module, mp *types.PackageMap) *Checker {
 	c := &Checker{
-		depth: 0,
-		mp:    mp,
+		depth:    0,
+		mp:       mp,
+		closures: make(map[*types.Scope]*types.Function),
 	}
 
 	m := types.NewModule(mod, mp.Packages[mod.Package.ID()])
@@ -48,9 +57,10 @@ func (c *Checker) GlobalFind(n string) (types.Symbol, bool) {
 	return c.ParentScope().Resolve(n, c.ParentScope())
 }
 
-func CheckPackages(pkgs []*ast.Package) (*types.PackageMap, error) {
+func CheckPackages(pkgs []*ast.Package, log *logging.Logger) (*types.PackageMap, error) {
 
 	mp := types.NewPackageMap()
+	log = log.For("typechecker")
 
 	for _, pkg := range pkgs {
 		tPkg := types.NewPackage(pkg)
@@ -58,7 +68,9 @@ func CheckPackages(pkgs []*ast.Package) (*types.PackageMap, error) {
 
 		// CheckModule
 		err := pkg.PerformInOrder(func(m *ast.Module) error {
+			log.Infof("checking module %s", m.Key())
 			c := New(m, mp)
+			c.log = log
 			mod, err := c.Check()
 
 			if err != nil {
@@ -77,21 +89,45 @@ func CheckPackages(pkgs []*ast.Package) (*types.PackageMap, error) {
 	return mp, nil
 }
 
+// Typechecks a single file, see PackageString
 func CheckString(str string) (*types.Module, error) {
+	pkg, err := PackageString(str)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mp, err := CheckPackages([]*ast.Package{pkg}, nil)
+
+	if err != nil {
+		return nil, err
+	}
 
+	for _, m := range pkg.Modules {
+		return mp.Modules[m.ID()], nil
+	}
+
+	return nil, errors.New("no module was checked")
+}
+
+// Parses a single file into the only module of a synthesized target package
+func PackageString(str string) (*ast.Package, error) {
 	file, errs := parser.ParseString(str)
 
 	if len(errs) != 0 {
 		return nil, errors.New(errs.String())
 	}
 
-	m := &ast.Module{
-		Set: &ast.FileSet{Files: []*ast.File{file}},
-	}
-	mp := types.NewPackageMap()
-	c := New(m, mp)
+	cfg := &fs.Config{}
+	cfg.Package.Name = file.ModuleName
+	pkg := ast.NewPackage(fs.NewPackage("", cfg))
+	pkg.IsTarget = true
+
+	m := ast.NewModule(nil, pkg)
+	m.Set = &ast.FileSet{ModuleName: file.ModuleName, Files: []*ast.File{file}}
+	pkg.AddModule(m)
 
-	return c.Check()
+	return pkg, nil
 }
 
 // TODO: Check cyclic function usage
@@ -1,8 +1,10 @@
 package typechecker
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/types"
 )
 
@@ -290,3 +292,633 @@ func TestExternDeclaration(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestStringInterpolation(t *testing.T) {
+	input := `
+		module main;
+
+		standard Stringable {
+			fn toString() -> string;
+		}
+
+		conform int to Stringable {
+			fn toString() -> string {
+				return "int";
+			}
+		}
+
+		struct Foo {
+			Value: int;
+		}
+
+		conform Foo to Stringable {
+			fn toString() -> string {
+				return "Foo(\(self.Value))";
+			}
+		}
+
+		fn main() {
+			const name = "world";
+			const count = 10;
+			const Stringable = "local";
+			const A = "hello \(name), \(count + 1) \("nested \(count)")";
+			const B = "\(Foo { Value: count })";
+		}
+	`
+
+	stringT := types.LookUp(types.String)
+	for name, typ := range checkLocals(t, input, "A", "B") {
+		if typ != stringT {
+			t.Errorf("expected %s to be a string, found %s", name, typ)
+		}
+	}
+
+	expectErrors(t, `
+		module main;
+
+		standard Stringable {
+			fn toString() -> string;
+		}
+
+		struct Foo {
+			Value: int;
+		}
+
+		fn main() {
+			const A = "\(Foo { Value: 1 })";
+		}
+	`, `"toString" on type "Foo"`)
+}
+
+// checks a valid program, returning the types of the given locals of main
+func checkLocals(t *testing.T, input string, names ...string) map[string]types.Type {
+	t.Helper()
+	res, err := CheckString(input)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fn := types.AsFunction(res.Scope.MustResolve("main"))
+	locals := make(map[string]types.Type)
+
+	for _, name := range names {
+		sym := fn.Scope.MustResolve(name)
+
+		if sym == nil {
+			t.Fatalf("%s is not in the scope of main", name)
+		}
+
+		locals[name] = sym.Type()
+	}
+
+	return locals
+}
+
+// returns the statements in the body of main
+func mainBody(t *testing.T, res *types.Module) []ast.Statement {
+	t.Helper()
+
+	for _, file := range res.AST.Set.Files {
+		for _, fn := range file.Nodes.Functions {
+			if fn.Func.Identifier.Value == "main" {
+				return fn.Func.Body.Statements
+			}
+		}
+	}
+
+	t.Fatal("main is not defined")
+	return nil
+}
+
+// checks an invalid program, expecting an error containing each message
+func expectErrors(t *testing.T, input string, msgs ...string) {
+	t.Helper()
+	_, err := CheckString(input)
+
+	if err == nil {
+		t.Fatalf("expected errors containing %q", msgs)
+	}
+
+	for _, msg := range msgs {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected an error containing %q, found %s", msg, err)
+		}
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	iterable := `
+		module main;
+
+		standard Iterable {
+			type Element;
+			fn count() -> int;
+			fn element(at index: int) -> Element;
+		}
+
+		struct Range {
+			Start: int;
+			End: int;
+		}
+
+		conform Range to Iterable {
+			type Element = int;
+
+			fn count() -> int {
+				return self.End - self.Start;
+			}
+
+			fn element(at index: int) -> int {
+				return self.Start + index;
+			}
+		}
+
+		struct Foo {
+			Value: int;
+		}
+	`
+
+	input := iterable + `
+		fn main() {
+			let total = 0;
+			for let i = 0; i < 10; i += 1 {
+				total += i;
+			}
+
+			const r = Range { Start: 0, End: 5 };
+			for x in r {
+				total += x;
+			}
+		}
+	`
+
+	locals := checkLocals(t, input, "total")
+	if locals["total"] != types.LookUp(types.Int) {
+		t.Errorf("expected total to be an int, found %s", locals["total"])
+	}
+
+	res, _ := CheckString(input)
+	for _, stmt := range mainBody(t, res) {
+		loop, ok := stmt.(*ast.ForInStatement)
+		if !ok {
+			continue
+		}
+
+		if typ := res.Table.GetNodeType(loop.Element); types.ResolveAliases(typ) != types.LookUp(types.Int) {
+			t.Errorf("expected the element of Range to be an int, found %s", typ)
+		}
+	}
+
+	// the collection must conform to Iterable
+	expectErrors(t, iterable+`
+		fn main() {
+			const f = Foo { Value: 1 };
+			for y in f {
+			}
+		}
+	`, `on type "Foo"`)
+
+	// the condition must be a bool
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			for let i = 0; i; i += 1 {
+			}
+		}
+	`, "expected `bool`")
+
+	// variables declared by the init statement are scoped to the loop
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			for let i = 0; i < 10; i += 1 {
+			}
+
+			const j = i;
+		}
+	`, "`i` is not defined")
+}
+
+func TestJumpStatements(t *testing.T) {
+	input := `
+		module main;
+
+		fn main() {
+			let found = false;
+			let steps = 0;
+			outer: while (true) {
+				for let i = 0; i < 10; i += 1 {
+					steps += 1;
+					switch i {
+						case 1:
+							break;
+						case 5:
+							found = true;
+							break outer;
+						default:
+							continue outer;
+					}
+				}
+				break;
+			}
+		}
+	`
+
+	locals := checkLocals(t, input, "found", "steps")
+	if locals["found"] != types.LookUp(types.Bool) {
+		t.Errorf("expected found to be a bool, found %s", locals["found"])
+	}
+
+	if locals["steps"] != types.LookUp(types.Int) {
+		t.Errorf("expected steps to be an int, found %s", locals["steps"])
+	}
+
+	tests := []struct {
+		body string
+		err  string
+	}{
+		{"continue;", "continue is not in a loop"},
+		{"break;", "break is not in a loop or switch"},
+		{"switch 1 { default: continue; }", "continue is not in a loop"},
+		{"while (true) { break missing; }", "unknown label \"missing\""},
+		{"sw: switch 1 { default: continue sw; }", "invalid continue label \"sw\", not a loop"},
+		{"a: while (true) { a: while (true) { break a; } }", "label \"a\" is already defined"},
+	}
+
+	for _, test := range tests {
+		expectErrors(t, "module main;\nfn main() {\n"+test.body+"\n}", test.err)
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `
+		module main;
+
+		fn main() {
+			const a = 5;
+			const b = if a > 10 { 1 } else if a > 4 { a } else { 3 };
+			const c = if a > 1 { true } else { false };
+			const d: i8 = if a > 1 { 1 } else { 2 };
+		}
+	`
+
+	locals := checkLocals(t, input, "b", "c", "d")
+	expected := map[string]types.Type{
+		"b": types.LookUp(types.Int),
+		"c": types.LookUp(types.Bool),
+		"d": types.LookUp(types.Int8),
+	}
+
+	for name, typ := range expected {
+		if locals[name] != typ {
+			t.Errorf("expected %s to be %s, found %s", name, typ, locals[name])
+		}
+	}
+
+	// the branches take the type the expression is assigned to, nested branches included
+	res, err := CheckString(`
+		module main;
+
+		fn main() {
+			const a = 5;
+			const z: u8 = 1;
+			const e: u8 = if a > 10 { 1 } else if a > 4 { z } else { 3 };
+		}
+	`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u8 := types.LookUp(types.UInt8)
+	outer := mainBody(t, res)[2].(*ast.VariableStatement).Value.(*ast.IfExpression)
+	inner := outer.Alternative.(*ast.IfExpression)
+
+	for _, n := range []ast.Expression{outer, outer.Action, inner, inner.Action, inner.Alternative} {
+		if typ := res.Table.GetNodeType(n); typ != u8 {
+			t.Errorf("expected %T to be u8, found %s", n, typ)
+		}
+	}
+
+	// the branches must yield the same type
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			const a = 5;
+			const c = if a > 1 { 1 } else { true };
+		}
+	`, "received `bool`")
+
+	// the condition must be a bool
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			const c = if 1 { 1 } else { 2 };
+		}
+	`, "expected `bool`")
+
+	// the branches must agree with the annotation
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			const z = 5;
+			const y: i8 = if z > 1 { z } else { 2 };
+		}
+	`, "expected `i8`, received `int`")
+}
+
+func TestLiteralUnification(t *testing.T) {
+	input := `
+		module main;
+
+		fn main() {
+			const a: i8 = 2;
+			const f: float = 2.0;
+			const b = 1 + a;
+			const c = a + 1;
+			const d: i8 = 1 + a;
+			const e = 1.5 * f;
+			const g = 1 + 2;
+		}
+	`
+
+	locals := checkLocals(t, input, "b", "c", "d", "e", "g")
+	expected := map[string]types.Type{
+		"b": types.LookUp(types.Int8),
+		"c": types.LookUp(types.Int8),
+		"d": types.LookUp(types.Int8),
+		"e": types.LookUp(types.Float),
+		"g": types.LookUp(types.Int),
+	}
+
+	// literals take the defined type they meet rather than the basic type underlying it
+	for name, typ := range expected {
+		if locals[name] != typ {
+			t.Errorf("expected %s to be %s, found %s (%T)", name, typ, locals[name], locals[name])
+		}
+	}
+
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			const a: i8 = 2;
+			const b: int = 1 + a;
+		}
+	`, "expected `int`, received `i8`")
+
+	expectErrors(t, `
+		module main;
+
+		fn main() {
+			const a: i8 = 2;
+			const b = 1.5 + a;
+		}
+	`, "i8")
+}
+
+func TestPipeExpression(t *testing.T) {
+	input := `
+		module main;
+
+		fn double(_ x: int) -> int {
+			return x * 2;
+		}
+
+		fn scale(value x: int, by y: int) -> int {
+			return x * y;
+		}
+
+		fn describe(count x: int) -> int {
+			return x;
+		}
+
+		fn describe(flag x: bool) -> bool {
+			return x;
+		}
+
+		fn main() {
+			const a = 1 + 2 |> double() |> scale(by: 2);
+			const b = a |> double() == 12;
+			const c = a |> describe();
+			const d = b |> describe();
+		}
+	`
+
+	locals := checkLocals(t, input, "a", "b", "c", "d")
+	expected := map[string]types.Type{
+		"a": types.LookUp(types.Int),
+		"b": types.LookUp(types.Bool),
+		"c": types.LookUp(types.Int),
+		"d": types.LookUp(types.Bool),
+	}
+
+	for name, typ := range expected {
+		if locals[name] != typ {
+			t.Errorf("expected %s to be %s, found %s", name, typ, locals[name])
+		}
+	}
+
+	// only the piped value takes the label of its parameter
+	expectErrors(t, `
+		module main;
+
+		fn scale(value x: int, by y: int) -> int {
+			return x * y;
+		}
+
+		fn main() {
+			const c = 3 |> scale(2);
+		}
+	`, "missing paramter label \"by\"")
+}
+
+func TestPipedOverloadSignature(t *testing.T) {
+	mod := types.NewModule(nil, nil)
+	param := func(label string, t types.Type) *types.Var {
+		v := types.NewVar("", t, mod)
+		v.ParamLabel = label
+		return v
+	}
+
+	intT := types.LookUp(types.Int)
+	fn := func(label string) *types.Function {
+		sg := types.NewFunctionSignature()
+		sg.AddParameter(param(label, intT))
+		sg.Result.SetType(intT)
+		return types.NewFunction("f", sg, mod)
+	}
+
+	set := types.NewFunctionSet(fn("count"))
+	set.Instances = append(set.Instances, fn("value"))
+
+	call := types.NewFunctionSignature()
+	call.AddParameter(param("", intT))
+
+	if options := set.FindPiped(call); options == nil || len(options.Instances) != 2 {
+		t.Fatalf("expected the piped value to match both overloads, found %v", options)
+	}
+
+	// the signature of the call site is left unlabeled
+	if label := call.Parameters[0].ParamLabel; label != "" {
+		t.Errorf("expected the piped value to remain unlabeled, found %q", label)
+	}
+}
+
+func TestClosureExpression(t *testing.T) {
+	input := `
+		module main;
+
+		struct Point {
+			x: int;
+			y: int;
+		}
+
+		fn main() {
+			let p = Point { x: 1, y: 2 };
+			const scale = 10;
+			const f = fn (a: int) -> int {
+				return a * scale + p.x;
+			};
+			const g = fn () {
+				const q = p;
+			};
+			const r = f(4);
+		}
+	`
+
+	locals := checkLocals(t, input, "f", "g", "r")
+	intT := types.LookUp(types.Int)
+
+	f, ok := locals["f"].(*types.FunctionSignature)
+	if !ok || len(f.Parameters) != 1 || f.Parameters[0].Type() != intT || f.Result.Type() != intT {
+		t.Errorf("expected f to be fn(int) -> int, found %s", locals["f"])
+	}
+
+	g, ok := locals["g"].(*types.FunctionSignature)
+	if !ok || len(g.Parameters) != 0 || g.Result.Type() != types.LookUp(types.Void) {
+		t.Errorf("expected g to be fn() -> void, found %s", locals["g"])
+	}
+
+	if locals["r"] != intT {
+		t.Errorf("expected r to be an int, found %s", locals["r"])
+	}
+
+	tests := []struct {
+		body string
+		err  string
+	}{
+		// captures are copies, so writes within the closure are rejected
+		{"let a = 1; const f = fn () { a = 2; };", "cannot assign to captured variable `a`"},
+		{"let p = Point { x: 1, y: 2 }; const f = fn () { p.x = 2; };", "cannot assign to captured variable `p`"},
+		{"let a = 1; const f = fn () { const g = fn () { a = 2; }; };", "cannot assign to captured variable `a`"},
+		{"const f = fn (a: int) -> int { return a; }; const b: bool = f(1);", "expected `bool`"},
+	}
+
+	for _, test := range tests {
+		expectErrors(t, "module main;\nstruct Point {\nx: int;\ny: int;\n}\nfn main() {\n"+test.body+"\n}", test.err)
+	}
+
+	expectErrors(t, `
+		module main;
+
+		const f = fn () {};
+	`, "closures must be declared within a function")
+
+	expectErrors(t, `
+		module main;
+
+		fn id<T>(_ a: T) -> T {
+			const f = fn () {};
+			return a;
+		}
+	`, "closures cannot be declared within generic functions")
+}
+
+func TestTuples(t *testing.T) {
+	input := `
+		module main;
+
+		fn divide(_ a: int, by b: int) -> (int, bool) {
+			if (b == 0) {
+				return (0, false);
+			}
+
+			return (a / b, true);
+		}
+
+		fn main() {
+			let (q, ok) = divide(84, by: 2);
+			let (_, bad) = divide(1, by: 0);
+			let (x: i8, y) = (1, 2);
+			const n = ((1, 2), true);
+			const e = n.0.1;
+			const s: (i8, int) = (1, 2);
+		}
+	`
+
+	locals := checkLocals(t, input, "q", "ok", "bad", "x", "y", "n", "e", "s")
+	intT, boolT, i8T := types.LookUp(types.Int), types.LookUp(types.Bool), types.LookUp(types.Int8)
+
+	expected := map[string]types.Type{
+		"q":   intT,
+		"ok":  boolT,
+		"bad": boolT,
+		"x":   i8T,
+		"y":   intT,
+		"e":   intT,
+	}
+
+	for name, typ := range expected {
+		if locals[name] != typ {
+			t.Errorf("expected %s to be %s, found %s", name, typ, locals[name])
+		}
+	}
+
+	tuples := map[string]string{
+		"n": "((int, int), bool)",
+		"s": "(i8, int)",
+	}
+
+	for name, str := range tuples {
+		if !types.IsTuple(locals[name]) || locals[name].String() != str {
+			t.Errorf("expected %s to be %s, found %s", name, str, locals[name])
+		}
+	}
+
+	// discarded elements are not defined
+	res, err := CheckString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sym := types.AsFunction(res.Scope.MustResolve("main")).Scope.MustResolve("_"); sym != nil {
+		t.Errorf("expected `_` to be discarded, found %s", sym)
+	}
+
+	tests := []struct {
+		body string
+		err  string
+	}{
+		{"let (a, b) = (1, 2, 3);", "into 2 elements"},
+		{"let (a, b, c) = (1, 2);", "into 3 elements"},
+		{"let (a, b) = 1;", "cannot destructure `literal int`, it is not a tuple"},
+		{"let (a: bool, b) = (1, 2);", "expected `bool`, received `literal int`"},
+		{"const a = 1; let (a, b) = (1, 2);", "invalid redeclaration of symbol \"a\""},
+		{"const t = (1, 2); const x = t.2;", "has no element 2"},
+		{"const t = (1, 2); const x = t.4294967295;", "has no element 4294967295"},
+		{"const a = 1; const x = a.0;", "it is not a tuple"},
+		{"const t = (1, none());", "tuple elements cannot be void"},
+		{"let (a, _) = (1, none());", "tuple elements cannot be void"},
+	}
+
+	for _, test := range tests {
+		expectErrors(t, "module main;\nfn none() {}\nfn main() {\n"+test.body+"\n}", test.err)
+	}
+}
@@ -11,8 +11,8 @@ import (
 // ---------------------- Checks ---------------------------------
 func (c *Checker) checkExpression(expr ast.Expression, ctx *NodeContext) {
 
-	fmt.Printf(
-		"Checking Expression: %T @ Line %d\n",
+	c.log.Debugf(
+		"checking expression: %T @ line %d",
 		expr,
 		expr.Range().Start.Line,
 	)
@@ -49,8 +49,8 @@ func (c *Checker) checkCallExpression(expr *ast.CallExpression, ctx *NodeContext
 
 	retType := c.evaluateCallExpression(expr, ctx)
 
-	if retType != types.LookUp(types.Void) || retType != unresolved {
-		fmt.Println("[WARNING] Call Expression returning non void value is unused")
+	if retType != types.LookUp(types.Void) && retType != unresolved {
+		c.log.Debugf("call expression returning non void value is unused @ line %d", expr.Range().Start.Line)
 	}
 
 	// check if function has not been resolved
@@ -61,21 +61,29 @@ func (c *Checker) checkCallExpression(expr *ast.CallExpression, ctx *NodeContext
 
 // ----------- Eval ------------------
 func (c *Checker) evaluateExpression(expr ast.Expression, ctx *NodeContext) types.Type {
-	fmt.Printf(
-		"Evaluating Expression: %T @ Line %d\n",
+	c.log.Debugf(
+		"evaluating expression: %T @ line %d",
 		expr,
 		expr.Range().Start.Line,
 	)
 	switch expr := expr.(type) {
 	// Literals
 	case *ast.IntegerLiteral:
+		if expr.Suffix != "" {
+			return c.evaluateSuffixedLiteral(expr, expr.Suffix)
+		}
 		return types.LookUp(types.IntegerLiteral)
 	case *ast.BooleanLiteral:
 		return types.LookUp(types.Bool)
 	case *ast.FloatLiteral:
+		if expr.Suffix != "" {
+			return c.evaluateSuffixedLiteral(expr, expr.Suffix)
+		}
 		return types.LookUp(types.FloatLiteral)
 	case *ast.StringLiteral:
 		return types.LookUp(types.String)
+	case *ast.InterpolatedStringLiteral:
+		return c.evaluateInterpolatedStringLiteral(expr, ctx)
 	case *ast.CharLiteral:
 		return types.LookUp(types.Char)
 	case *ast.NilLiteral:
@@ -103,10 +111,16 @@ func (c *Checker) evaluateExpression(expr ast.Expression, ctx *NodeContext) type
 		return c.evaluateSpecializationExpression(expr, ctx)
 	case *ast.ArrayLiteral:
 		return c.evaluateArrayLiteral(expr, ctx)
+	case *ast.TupleLiteral:
+		return c.evaluateTupleLiteral(expr, ctx)
 	case *ast.MapLiteral:
 		return c.evaluateMapLiteral(expr, ctx)
 	case *ast.IndexExpression:
 		return c.evaluateIndexExpression(expr, ctx)
+	case *ast.IfExpression:
+		return c.evaluateIfExpression(expr, ctx)
+	case *ast.FunctionExpression:
+		return c.evaluateClosureExpression(expr, ctx)
 	default:
 		msg := fmt.Sprintf("expression evaluation not implemented, %T", expr)
 		panic(msg)
@@ -133,9 +147,88 @@ func (c *Checker) evaluateIdentifierExpression(expr *ast.IdentifierExpression, c
 		)
 	}
 
+	// variables of enclosing functions are captured by the closures referencing them
+	if v, ok := s.(*types.Var); ok {
+		closures := c.capturingClosures(v, ctx.scope)
+
+		if len(closures) != 0 && types.IsUnresolved(v.Type()) {
+			c.addError(
+				fmt.Sprintf("`%s` cannot be captured before it is initialized", expr.Value),
+				expr.Range(),
+			)
+
+			return unresolved
+		}
+
+		for _, fn := range closures {
+			fn.Capture(v)
+		}
+	}
+
 	return s.Type()
 }
 
+// Returns the closures lying between a scope & the scope defining a variable, innermost first. Globals are never captured
+func (c *Checker) capturingClosures(v *types.Var, scope *types.Scope) []*types.Function {
+	closures := []*types.Function{}
+
+	for s := scope; s != nil && s != c.ParentScope(); s = s.Parent {
+		if s.ResolveInCurrent(v.Name()) == v {
+			return closures
+		}
+
+		if fn, ok := c.closures[s]; ok {
+			closures = append(closures, fn)
+		}
+	}
+
+	return nil
+}
+
+// closures capture variables by value, so assignments made within a closure, to a variable or its fields, would not be seen by the function declaring the variable
+func (c *Checker) checkCapturedAssignment(target ast.Expression, ctx *NodeContext) {
+	root := target
+	for {
+		access, ok := root.(*ast.FieldAccessExpression)
+		if !ok {
+			break
+		}
+
+		root = access.Target
+	}
+
+	ident, ok := root.(*ast.IdentifierExpression)
+	if !ok {
+		return
+	}
+
+	s, ok := ctx.scope.Resolve(ident.Value, c.ParentScope())
+	if !ok {
+		return
+	}
+
+	v := types.AsVar(s)
+	if v == nil {
+		return
+	}
+
+	// fields assigned through a pointer belong to the value it points to
+	if root != target && types.IsPointer(v.Type()) {
+		return
+	}
+
+	if len(c.capturingClosures(v, ctx.scope)) != 0 {
+		c.addError(fmt.Sprintf("cannot assign to captured variable `%s`", ident.Value), target.Range())
+	}
+}
+
+// suffixed literals have the type named by their suffix, rather than a group literal type
+func (c *Checker) evaluateSuffixedLiteral(expr ast.Expression, suffix string) types.Type {
+	t := types.LookUpSuffix(suffix)
+	c.module.Table.SetNodeType(expr, t)
+	return t
+}
+
 func (c *Checker) evaluateGroupedExpression(expr *ast.GroupedExpression, ctx *NodeContext) types.Type {
 	return c.evaluateExpression(expr.Expr, ctx)
 }
@@ -162,7 +255,7 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 		}
 
 		c.module.Table.SetNodeType(expr.Target, typ)
-		ctx.sg.Function.AddCallEdge(typ)
+		c.addCallEdge(ctx, typ)
 		specializations := make(types.Specialization)
 		for i, arg := range expr.Arguments {
 			param := fn.InstanceOf.Parameters[i]
@@ -173,7 +266,8 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 				return fn.ReturnType()
 			}
 
-			if param.ParamLabel != arg.GetLabel() {
+			// the piped value of `x |> f(y)` takes the label of its parameter
+			if param.ParamLabel != arg.GetLabel() && !(i == 0 && expr.IsPiped()) {
 				c.addError(fmt.Sprintf("missing paramter label \"%s\"", param.ParamLabel), arg.Range())
 			}
 
@@ -221,7 +315,7 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 		for i, arg := range expr.Arguments {
 			expected := fn.Parameters[i]
 
-			if expected.ParamLabel != arg.GetLabel() {
+			if expected.ParamLabel != arg.GetLabel() && !(i == 0 && expr.IsPiped()) {
 				c.addError(fmt.Sprintf("missing paramter label \"%s\"", expected.ParamLabel), arg.Range())
 			}
 
@@ -244,7 +338,7 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 		// return signature if not generic
 		if !isGeneric {
 			c.module.Table.SetNodeType(expr.Target, fn)
-			ctx.sg.Function.AddCallEdge(fn)
+			c.addCallEdge(ctx, fn)
 			return fn.Result.Type()
 		}
 
@@ -257,7 +351,7 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 		}
 
 		c.module.Table.SetNodeType(expr.Target, t)
-		ctx.sg.Function.AddCallEdge(t)
+		c.addCallEdge(ctx, t)
 
 		switch t := t.(type) {
 		case *types.FunctionSignature:
@@ -287,7 +381,12 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 		}
 
 		// Find Possible Options
-		options := set.Find(callSg, false)
+		var options *types.FunctionSet
+		if expr.IsPiped() {
+			options = set.FindPiped(callSg)
+		} else {
+			options = set.Find(callSg, false)
+		}
 
 		// No Options found
 		if options == nil {
@@ -297,7 +396,7 @@ func (c *Checker) evaluateCallExpression(expr *ast.CallExpression, ctx *NodeCont
 
 		// check is there is an exact match
 		if single, ok := options.GetAsSingle(); ok {
-			fmt.Println("\t[OVERLOAD] Exact match", single.Sg())
+			c.log.Debugf("overload exact match, %s", single.Sg())
 
 			c.module.Table.SetNodeType(expr.Target, single.Sg())
 
@@ -424,8 +523,64 @@ func (c *Checker) evaluateBinaryExpression(e *ast.BinaryExpression, ctx *NodeCon
 
 }
 
+func (c *Checker) evaluateIfExpression(n *ast.IfExpression, ctx *NodeContext) types.Type {
+
+	// 1 - Condition
+	condition := c.evaluateExpression(n.Condition, ctx)
+
+	_, err := c.validate(types.LookUp(types.Bool), condition)
+
+	if err != nil {
+		c.addError(err.Error(), n.Condition.Range())
+		return unresolved
+	}
+
+	// 2 - Branches
+	action := c.evaluateExpression(n.Action, ctx)
+	alternative := c.evaluateExpression(n.Alternative, ctx)
+
+	if types.IsUnresolved(action) || types.IsUnresolved(alternative) {
+		return unresolved
+	}
+
+	// 3 - Unify, both branches must yield the same type
+	typ, err := c.validate(action, alternative)
+
+	if err != nil {
+		c.addError(err.Error(), n.Alternative.Range())
+		return unresolved
+	}
+
+	c.module.Table.SetNodeType(n.Action, action)
+	c.module.Table.SetNodeType(n.Alternative, alternative)
+	c.setIfExpressionType(n, typ)
+	return typ
+}
+
+// Checks each branch of an if expression against the type it yields, the unified type of its branches or the type it is assigned to, so literal branches are lowered as that type
+func (c *Checker) setIfExpressionType(n *ast.IfExpression, t types.Type) {
+	c.module.Table.SetNodeType(n, t)
+
+	for _, branch := range []ast.Expression{n.Action, n.Alternative} {
+		_, err := c.validate(t, c.module.Table.GetNodeType(branch))
+
+		if err != nil {
+			c.addError(err.Error(), branch.Range())
+			continue
+		}
+
+		if nested, ok := branch.(*ast.IfExpression); ok {
+			c.setIfExpressionType(nested, t)
+			continue
+		}
+
+		c.module.Table.SetNodeType(branch, t)
+	}
+}
+
 func (c *Checker) evaluateAssignmentExpression(expr *ast.AssignmentExpression, ctx *NodeContext) types.Type {
 
+	c.checkCapturedAssignment(expr.Target, ctx)
 	lhs := c.evaluateExpression(expr.Target, ctx)
 	rhs := c.evaluateExpression(expr.Value, ctx)
 
@@ -440,6 +595,7 @@ func (c *Checker) evaluateAssignmentExpression(expr *ast.AssignmentExpression, c
 }
 
 func (c *Checker) evaluateShorthandAssignmentExpression(expr *ast.ShorthandAssignmentExpression, ctx *NodeContext) types.Type {
+	c.checkCapturedAssignment(expr.Target, ctx)
 	lhs := c.evaluateExpression(expr.Target, ctx)
 	rhs := c.evaluateExpression(expr.Right, ctx)
 
@@ -569,7 +725,7 @@ func (c *Checker) resolveVar(f *types.Var, v ast.Expression, specializations typ
 		return fmt.Errorf("unresolved type assigned for `%s`", f.Name())
 	}
 
-	fmt.Println("\n", "\t[Resolver] Variable Name", f.Name(), "\n", "\t[Resolver] Variable Type", f.Type(), "\n", "\t[Resolver] Provided Type", vT)
+	c.log.Debugf("resolving variable `%s` of type `%s`, provided `%s`", f.Name(), f.Type(), vT)
 	// check constraints & specialize
 	// can either be a type param or generic struct or a generic function
 	fT := types.ResolveAliases(f.Type())
@@ -580,7 +736,7 @@ func (c *Checker) resolveVar(f *types.Var, v ast.Expression, specializations typ
 		return err
 	}
 
-	fmt.Println("\t[Resolver] Specializing", fT, "with", vT)
+	c.log.Debugf("specializing `%s` with `%s`", fT, vT)
 	switch fT := fT.(type) {
 	case *types.TypeParam:
 		return c.specialize(specializations, fT, vT, v)
@@ -644,6 +800,8 @@ func (c *Checker) evaluateFieldAccessExpression(n *ast.FieldAccessExpression, ct
 	switch p := n.Field.(type) {
 	case *ast.IdentifierExpression:
 		field = p.Value
+	case *ast.IntegerLiteral:
+		return c.evaluateTupleElementAccess(n, a, p)
 	default:
 		if a, ok := a.(*types.Module); ok {
 			sc := a.Scope
@@ -672,6 +830,25 @@ func (c *Checker) evaluateFieldAccessExpression(n *ast.FieldAccessExpression, ct
 	return symbolType
 }
 
+// the elements of a tuple are accessed by their index, `pair.0`
+func (c *Checker) evaluateTupleElementAccess(n *ast.FieldAccessExpression, target types.Type, index *ast.IntegerLiteral) types.Type {
+	tuple, ok := types.ResolveAliases(target).(*types.Tuple)
+
+	if !ok {
+		c.addError(fmt.Sprintf("cannot access element %d of `%s`, it is not a tuple", index.Value, target), n.Range())
+		return unresolved
+	}
+
+	if index.Value >= uint64(len(tuple.Elements)) {
+		c.addError(fmt.Sprintf("tuple `%s` has no element %d", tuple, index.Value), index.Range())
+		return unresolved
+	}
+
+	element := tuple.Elements[index.Value]
+	c.module.Table.SetNodeType(n.Field, element)
+	return element
+}
+
 func (c *Checker) evaluateSpecializationExpression(e *ast.SpecializationExpression, ctx *NodeContext) types.Type {
 
 	// 1- Find Target
@@ -721,6 +898,101 @@ func (c *Checker) evaluateFunctionExpression(e *ast.FunctionExpression) types.Ty
 	return sg
 }
 
+/*
+Checks an anonymous function where it is written, its body may reference the variables of the functions enclosing it
+
+# Example
+
+`let add = fn (x: int, y: int) -> int { return x + y; }`
+*/
+func (c *Checker) evaluateClosureExpression(e *ast.FunctionExpression, ctx *NodeContext) types.Type {
+	if ctx.sg == nil {
+		c.addError("closures must be declared within a function", e.Range())
+		return unresolved
+	}
+
+	enclosing := ctx.sg.Function
+	if types.IsGeneric(ctx.sg) || (enclosing.Self != nil && types.IsGeneric(enclosing.Self.Type())) {
+		c.addError("closures cannot be declared within generic functions", e.Range())
+		return unresolved
+	}
+
+	sg := types.NewFunctionSignature()
+	def := types.NewFunction("closure", sg, c.module)
+	def.Scope = types.NewScope(ctx.scope, "closure")
+	c.closures[def.Scope] = def
+
+	// Parameters, closures are called without labels
+	for _, p := range e.Parameters {
+		v := types.NewVar(p.Name.Value, unresolved, c.module)
+		sg.AddParameter(v)
+
+		t := c.evaluateTypeExpression(p.Type, nil, ctx)
+		err := c.validateAssignment(v, t, p, true)
+		if err != nil {
+			c.addError(err.Error(), p.Range())
+		}
+
+		if p.Name.Value == "_" {
+			continue
+		}
+
+		err = def.Scope.Define(v)
+		if err != nil {
+			c.addError(err.Error(), p.Range())
+		}
+	}
+
+	// Annotated Return Type
+	if e.ReturnType != nil {
+		sg.Result = types.NewVar("result", unresolved, c.module)
+		t := c.evaluateTypeExpression(e.ReturnType, nil, ctx)
+		err := c.validateAssignment(sg.Result, t, e.ReturnType, true)
+
+		if err != nil {
+			c.addError(err.Error(), e.ReturnType.Range())
+		}
+	} else {
+		sg.Result = types.NewVar("result", types.LookUp(types.Void), c.module)
+	}
+
+	c.module.Table.SetNodeType(e, sg)
+	c.module.Table.SetSymbol(def, e)
+	c.module.Table.SetClosure(e, def)
+
+	// Body, jumps may not leave the closure
+	targets, labels := c.targets, c.labels
+	c.targets, c.labels = nil, nil
+	c.checkBlockStatement(e.Body, NewContext(def.Scope, sg, nil))
+	c.targets, c.labels = targets, labels
+
+	return sg
+}
+
+func (c *Checker) evaluateTupleLiteral(n *ast.TupleLiteral, ctx *NodeContext) types.Type {
+	elements := []types.Type{}
+
+	for _, node := range n.Elements {
+		provided := c.evaluateExpression(node, ctx)
+
+		if types.IsUnresolved(provided) {
+			return unresolved
+		}
+
+		if provided == types.LookUp(types.Void) {
+			c.addError("tuple elements cannot be void", node.Range())
+			return unresolved
+		}
+
+		elements = append(elements, provided)
+	}
+
+	// nested literals keep this type, the outermost literal is retyped once it is validated
+	t := types.ResolveLiteral(types.NewTuple(elements))
+	c.module.Table.SetNodeType(n, t)
+	return types.NewTuple(elements)
+}
+
 func (c *Checker) evaluateArrayLiteral(n *ast.ArrayLiteral, ctx *NodeContext) types.Type {
 
 	var element types.Type
@@ -823,6 +1095,48 @@ func (c *Checker) evaluateMapLiteral(n *ast.MapLiteral, ctx *NodeContext) types.
 	return instance
 }
 
+func (c *Checker) evaluateInterpolatedStringLiteral(n *ast.InterpolatedStringLiteral, ctx *NodeContext) types.Type {
+	var standard *types.Standard
+
+	for _, part := range n.Parts {
+		if _, ok := part.(*ast.StringLiteral); ok {
+			continue
+		}
+
+		// 1 - Eval Interpolated Value
+		provided := c.evaluateExpression(part, NewContext(ctx.scope, ctx.sg, nil))
+
+		if provided == unresolved || types.ResolveAliases(provided) == types.LookUp(types.String) {
+			continue
+		}
+
+		// 2 - Eval Stringable Standard, resolved as conformances resolve it so locals cannot shadow it
+		if standard == nil {
+			symbol, ok := c.GlobalFind("Stringable")
+
+			if !ok {
+				c.addError("unable to find stringable standard", part.Range())
+				return unresolved
+			}
+
+			standard = types.AsStandard(symbol.Type().Parent())
+			if standard == nil {
+				c.addError("stringable is not a standard", part.Range())
+				return unresolved
+			}
+		}
+
+		// 3 - Validate Conformance to Stringable Standard
+		err := types.Conforms([]*types.Standard{standard}, provided)
+
+		if err != nil {
+			c.addError(err.Error(), part.Range())
+		}
+	}
+
+	return types.LookUp(types.String)
+}
+
 func (c *Checker) evaluateIndexExpression(n *ast.IndexExpression, ctx *NodeContext) types.Type {
 
 	// 1 - Eval Target
@@ -8,8 +8,8 @@ import (
 )
 
 func (c *Checker) checkStatement(stmt ast.Statement, ctx *NodeContext) {
-	fmt.Printf(
-		"Checking Statement: %T @ Line %d\n",
+	c.log.Debugf(
+		"checking statement: %T @ line %d",
 		stmt,
 		stmt.Range().Start.Line,
 	)
@@ -35,9 +35,17 @@ func (c *Checker) checkStatement(stmt ast.Statement, ctx *NodeContext) {
 	case *ast.SwitchStatement:
 		c.checkSwitchStatement(stmt, ctx)
 	case *ast.BreakStatement:
-		return // nothing to TC on break
+		c.checkBreakStatement(stmt)
+	case *ast.ContinueStatement:
+		c.checkContinueStatement(stmt)
+	case *ast.LabeledStatement:
+		c.checkLabeledStatement(stmt, ctx)
 	case *ast.WhileStatement:
 		c.checkWhileStatement(stmt, ctx)
+	case *ast.ForStatement:
+		c.checkForStatement(stmt, ctx)
+	case *ast.ForInStatement:
+		c.checkForInStatement(stmt, ctx)
 	case *ast.TypeStatement:
 		c.checkTypeStatement(stmt, ctx)
 	case *ast.DereferenceAssignmentStatement:
@@ -59,6 +67,10 @@ func (c *Checker) checkBlockStatement(blk *ast.BlockStatement, ctx *NodeContext)
 }
 
 func (c *Checker) checkVariableStatement(stmt *ast.VariableStatement, ctx *NodeContext, global bool) {
+	if stmt.Identifier == nil {
+		c.checkDestructuringStatement(stmt, ctx)
+		return
+	}
 
 	var def *types.Var
 	if !global {
@@ -112,6 +124,74 @@ func (c *Checker) checkVariableStatement(stmt *ast.VariableStatement, ctx *NodeC
 	}
 }
 
+// binds each element of a tuple to a name, `let (a, b) = f();`, elements named `_` are discarded
+func (c *Checker) checkDestructuringStatement(stmt *ast.VariableStatement, ctx *NodeContext) {
+	defs := []*types.Var{}
+
+	for _, ident := range stmt.Elements {
+		def := types.NewVar(ident.Value, unresolved, c.module)
+		def.Mutable = !stmt.IsConstant
+		defs = append(defs, def)
+
+		if ident.Value == "_" {
+			continue
+		}
+
+		err := ctx.scope.Define(def)
+
+		if err != nil {
+			c.addError(
+				fmt.Sprintf(err.Error(), def.Name()),
+				ident.Range(),
+			)
+			return
+		}
+	}
+
+	initializer := c.evaluateExpression(stmt.Value, ctx)
+
+	if types.IsUnresolved(initializer) {
+		return
+	}
+
+	tuple, ok := types.ResolveAliases(initializer).(*types.Tuple)
+
+	if !ok {
+		c.addError(fmt.Sprintf("cannot destructure `%s`, it is not a tuple", initializer), stmt.Value.Range())
+		return
+	}
+
+	if len(tuple.Elements) != len(stmt.Elements) {
+		c.addError(
+			fmt.Sprintf("cannot destructure `%s` into %d elements", tuple, len(stmt.Elements)),
+			stmt.Value.Range(),
+		)
+		return
+	}
+
+	elements := []types.Type{}
+
+	for i, ident := range stmt.Elements {
+		def := defs[i]
+
+		// Check Annotation
+		if t := ident.AnnotatedType; t != nil {
+			def.SetType(c.evaluateTypeExpression(t, nil, ctx))
+		}
+
+		err := c.validateAssignment(def, tuple.Elements[i], ident, false)
+		if err != nil {
+			c.addError(err.Error(), ident.Range())
+			return
+		}
+
+		elements = append(elements, def.Type())
+	}
+
+	// the initializer takes the types of the elements it is bound to
+	c.module.Table.SetNodeType(stmt.Value, types.NewTuple(elements))
+}
+
 func (c *Checker) checkReturnStatement(stmt *ast.ReturnStatement, ctx *NodeContext) {
 
 	if ctx.sg == nil {
@@ -151,8 +231,11 @@ func (c *Checker) checkIfStatement(stmt *ast.IfStatement, ctx *NodeContext) {
 	c.checkBlockStatement(stmt.Action, newCtx)
 
 	// 3 - Check Alternative
-	if stmt.Alternative != nil {
-		c.checkBlockStatement(stmt.Alternative, newCtx)
+	switch alt := stmt.Alternative.(type) {
+	case *ast.BlockStatement:
+		c.checkBlockStatement(alt, newCtx)
+	case *ast.IfStatement:
+		c.checkIfStatement(alt, ctx)
 	}
 }
 
@@ -291,6 +374,8 @@ func (c *Checker) checkEnumStatement(n *ast.EnumStatement, ctx *NodeContext) {
 }
 
 func (c *Checker) checkSwitchStatement(n *ast.SwitchStatement, ctx *NodeContext) {
+	c.enterTarget(n)
+	defer c.exitTarget()
 
 	// 1 - Condition
 	condition := c.evaluateExpression(n.Condition, ctx)
@@ -342,6 +427,9 @@ func (c *Checker) checkSwitchStatement(n *ast.SwitchStatement, ctx *NodeContext)
 }
 
 func (c *Checker) checkWhileStatement(n *ast.WhileStatement, ctx *NodeContext) {
+	c.enterTarget(n)
+	defer c.exitTarget()
+
 	condition := c.evaluateExpression(n.Condition, ctx)
 
 	_, err := c.validate(types.LookUp(types.Bool), condition)
@@ -356,6 +444,167 @@ func (c *Checker) checkWhileStatement(n *ast.WhileStatement, ctx *NodeContext) {
 	c.checkBlockStatement(n.Action, newCtx)
 }
 
+func (c *Checker) checkForStatement(n *ast.ForStatement, ctx *NodeContext) {
+	c.enterTarget(n)
+	defer c.exitTarget()
+
+	// variables declared by the init statement are scoped to the loop
+	scope := types.NewScope(ctx.scope, fmt.Sprintf("__for_Header__%v", n))
+	newCtx := NewContext(scope, ctx.sg, ctx.lhs)
+
+	// 1 - Init
+	if n.Init != nil {
+		c.checkStatement(n.Init, newCtx)
+	}
+
+	// 2 - Condition
+	if n.Condition != nil {
+		condition := c.evaluateExpression(n.Condition, newCtx)
+
+		_, err := c.validate(types.LookUp(types.Bool), condition)
+
+		if err != nil {
+			c.addError(err.Error(), n.Condition.Range())
+			return
+		}
+	}
+
+	// 3 - Post
+	if n.Post != nil {
+		c.checkExpression(n.Post, newCtx)
+	}
+
+	// 4 - Body
+	scope = types.NewScope(scope, fmt.Sprintf("__for_Block__%v", n))
+	newCtx = NewContext(scope, ctx.sg, ctx.lhs)
+	c.checkBlockStatement(n.Action, newCtx)
+}
+
+func (c *Checker) checkForInStatement(n *ast.ForInStatement, ctx *NodeContext) {
+	c.enterTarget(n)
+	defer c.exitTarget()
+
+	// 1 - Eval Collection
+	collection := c.evaluateExpression(n.Collection, ctx)
+
+	if collection == unresolved {
+		return
+	}
+
+	// 2 - Eval Iterable Standard
+	symbol, ok := ctx.scope.Resolve("Iterable", c.ParentScope())
+
+	if !ok {
+		c.addError("unable to find iterable standard", n.Collection.Range())
+		return
+	}
+
+	standard := types.AsStandard(symbol.Type().Parent())
+	if standard == nil {
+		c.addError("iterable is not a standard", n.Collection.Range())
+		return
+	}
+
+	// 3 - Validate Conformance to Iterable Standard
+	err := types.Conforms([]*types.Standard{standard}, collection)
+
+	if err != nil {
+		c.addError(err.Error(), n.Collection.Range())
+		return
+	}
+
+	// 4 - Get Element Type
+	element := types.ResolveType(collection, "Element")
+
+	if element == nil {
+		c.addError("Unable to locate Element Type", n.Collection.Range())
+		return
+	}
+
+	// 5 - Define Element & Check Body
+	scope := types.NewScope(ctx.scope, fmt.Sprintf("__for_Block__%v", n))
+	newCtx := NewContext(scope, ctx.sg, ctx.lhs)
+
+	err = scope.Define(types.NewVar(n.Element.Value, element, c.module))
+
+	if err != nil {
+		c.addError(fmt.Sprintf(err.Error(), n.Element.Value), n.Element.Range())
+		return
+	}
+
+	c.module.Table.SetNodeType(n.Element, element)
+	c.checkBlockStatement(n.Action, newCtx)
+}
+
+func (c *Checker) enterTarget(n ast.Statement) {
+	c.targets = append(c.targets, n)
+}
+
+func (c *Checker) exitTarget() {
+	c.targets = c.targets[:len(c.targets)-1]
+}
+
+func (c *Checker) checkLabeledStatement(n *ast.LabeledStatement, ctx *NodeContext) {
+	for _, l := range c.labels {
+		if l.Label.Value == n.Label.Value {
+			c.addError(fmt.Sprintf("label \"%s\" is already defined", n.Label.Value), n.Label.Range())
+			break
+		}
+	}
+
+	c.labels = append(c.labels, n)
+	defer func() {
+		c.labels = c.labels[:len(c.labels)-1]
+	}()
+
+	c.checkStatement(n.Stmt, ctx)
+}
+
+func (c *Checker) checkBreakStatement(n *ast.BreakStatement) {
+	if n.Label != nil {
+		c.resolveLabel(n.Label, false)
+		return
+	}
+
+	if len(c.targets) == 0 {
+		c.addError("break is not in a loop or switch", n.Range())
+	}
+}
+
+func (c *Checker) checkContinueStatement(n *ast.ContinueStatement) {
+	if n.Label != nil {
+		c.resolveLabel(n.Label, true)
+		return
+	}
+
+	for _, t := range c.targets {
+		if _, ok := t.(*ast.SwitchStatement); !ok {
+			return
+		}
+	}
+
+	c.addError("continue is not in a loop", n.Range())
+}
+
+// validates the label of a `break` or `continue` statement names an enclosing statement it may target
+func (c *Checker) resolveLabel(label *ast.IdentifierExpression, isContinue bool) {
+	for i := len(c.labels) - 1; i >= 0; i-- {
+		l := c.labels[i]
+
+		if l.Label.Value != label.Value {
+			continue
+		}
+
+		if _, ok := l.Stmt.(*ast.SwitchStatement); ok && isContinue {
+			c.addError(fmt.Sprintf("invalid continue label \"%s\", not a loop", label.Value), label.Range())
+		}
+
+		return
+	}
+
+	c.addError(fmt.Sprintf("unknown label \"%s\"", label.Value), label.Range())
+}
+
 func (c *Checker) checkTypeStatement(n *ast.TypeStatement, ctx *NodeContext) {
 
 	// Fetch Alias
@@ -383,7 +632,7 @@ func (c *Checker) checkTypeStatement(n *ast.TypeStatement, ctx *NodeContext) {
 	var RHS types.Type
 
 	if n.Value != nil {
-		fmt.Println("[DEBUG] Resolving RHS for Alias", n.Identifier.Value)
+		c.log.Debugf("resolving rhs for alias `%s`", n.Identifier.Value)
 		RHS = c.evaluateTypeExpression(n.Value, alias.TypeParameters, ctx)
 		alias.SetType(RHS)
 	}
@@ -21,6 +21,10 @@ func (c *Checker) evaluateTypeExpression(e ast.TypeExpression, tPs []*types.Type
 		return c.evaluateTypeSpecializationExpression(expr, tPs, ctx)
 	case *ast.FieldAccessExpression:
 		return c.evaluateTypeFieldAccessExpression(expr, tPs, ctx)
+	case *ast.FunctionTypeExpression:
+		return c.evaluateFunctionTypeExpression(expr, tPs, ctx)
+	case *ast.TupleTypeExpression:
+		return c.evaluateTupleTypeExpression(expr, tPs, ctx)
 	default:
 		msg := fmt.Sprintf("type expression check not implemented, %T", e)
 		panic(msg)
@@ -56,6 +60,34 @@ func (c *Checker) evaluateIdentifierTypeExpression(expr *ast.IdentifierExpressio
 	return typ
 }
 
+// function types describe the signature of a value, its parameters are unlabeled
+func (c *Checker) evaluateFunctionTypeExpression(expr *ast.FunctionTypeExpression, tPs []*types.TypeParam, ctx *NodeContext) types.Type {
+	sg := types.NewFunctionSignature()
+
+	for _, arg := range expr.Arguments {
+		t := c.evaluateTypeExpression(arg, tPs, ctx)
+		sg.AddParameter(types.NewVar("", t, c.module))
+	}
+
+	if expr.ReturnType != nil {
+		sg.Result = types.NewVar("result", c.evaluateTypeExpression(expr.ReturnType, tPs, ctx), c.module)
+	} else {
+		sg.Result = types.NewVar("result", types.LookUp(types.Void), c.module)
+	}
+
+	return sg
+}
+
+func (c *Checker) evaluateTupleTypeExpression(expr *ast.TupleTypeExpression, tPs []*types.TypeParam, ctx *NodeContext) types.Type {
+	elements := []types.Type{}
+
+	for _, e := range expr.Elements {
+		elements = append(elements, c.evaluateTypeExpression(e, tPs, ctx))
+	}
+
+	return types.NewTuple(elements)
+}
+
 func (c *Checker) evaluatePointerTypeExpression(expr *ast.PointerTypeExpression, tPs []*types.TypeParam, ctx *NodeContext) types.Type {
 
 	n := expr.PointerTo
@@ -1,8 +1,6 @@
 package typechecker
 
 import (
-	"fmt"
-
 	"github.com/mantton/calypso/internal/calypso/ast"
 	"github.com/mantton/calypso/internal/calypso/lexer"
 	"github.com/mantton/calypso/internal/calypso/token"
@@ -17,6 +15,15 @@ func (c *Checker) addError(msg string, pos token.SyntaxRange) {
 	})
 }
 
+// records the call in the call graph of the enclosing function, calls in top level constants have no caller
+func (c *Checker) addCallEdge(ctx *NodeContext, t types.Type) {
+	if ctx.sg == nil {
+		return
+	}
+
+	ctx.sg.Function.AddCallEdge(t)
+}
+
 func (c *Checker) specialize(m types.Specialization, tParam *types.TypeParam, provided types.Type, expr ast.Expression) error {
 	currentSpec, ok := m[tParam]
 
@@ -30,7 +37,7 @@ func (c *Checker) specialize(m types.Specialization, tParam *types.TypeParam, pr
 		}
 
 		m[tParam] = provided
-		fmt.Printf("\t[Resolver] Specialized %s as %s\n", tParam, provided)
+		c.log.Debugf("specialized `%s` as `%s`", tParam, provided)
 		return nil
 	}
 
@@ -42,7 +49,7 @@ func (c *Checker) specialize(m types.Specialization, tParam *types.TypeParam, pr
 	if err != nil {
 		return err
 	}
-	fmt.Printf("\t[Resolver] Specialization Exist, Assignment Validated %s as %s\n", tParam, provided)
+	c.log.Debugf("specialization exists, validated `%s` as `%s`", tParam, provided)
 
 	return nil
 
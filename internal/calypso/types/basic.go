@@ -155,11 +155,50 @@ func ResolveLiteral(t Type) Type {
 	case *Pointer:
 		ptr := ResolveLiteral(t.PointerTo)
 		return NewPointer(ptr)
+	case *Tuple:
+		elements := []Type{}
+		for _, e := range t.Elements {
+			elements = append(elements, ResolveLiteral(e))
+		}
+		return NewTuple(elements)
 	}
 
 	return t
 }
 
+// the types numeric literals may be suffixed with & their widths in bits
+var literalSuffixes = map[string]struct {
+	typ  BasicType
+	bits int
+}{
+	"i8":  {Int8, 8},
+	"i16": {Int16, 16},
+	"i32": {Int32, 32},
+	"i64": {Int64, 64},
+	"u8":  {UInt8, 8},
+	"u16": {UInt16, 16},
+	"u32": {UInt32, 32},
+	"u64": {UInt64, 64},
+	"f":   {Float, 32},
+	"d":   {Double, 64},
+}
+
+// returns the type named by the suffix of a numeric literal, e.g. `u8` in `10u8`
+func LookUpSuffix(suffix string) Type {
+	s, ok := literalSuffixes[suffix]
+	if !ok {
+		return LookUp(Unresolved)
+	}
+
+	return LookUp(s.typ)
+}
+
+// returns the width in bits of the type named by the suffix of a numeric literal, reporting whether the suffix names a type
+func SuffixWidth(suffix string) (int, bool) {
+	s, ok := literalSuffixes[suffix]
+	return s.bits, ok
+}
+
 func IsBoolean(t Type) bool {
 	switch t := t.(type) {
 	case *DefinedType:
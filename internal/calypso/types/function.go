@@ -18,6 +18,8 @@ type Function struct {
 
 	CallGraph map[Type]struct{}
 	specs     map[string]*SpecializedFunctionSignature
+
+	Captures []*Var // the variables of enclosing functions referenced by a closure, in order of first reference
 }
 
 func (t *Function) String() string {
@@ -159,6 +161,16 @@ func (fn *Function) AddCallEdge(t Type) {
 	fn.CallGraph[t] = struct{}{}
 }
 
+func (fn *Function) Capture(v *Var) {
+	for _, c := range fn.Captures {
+		if c == v {
+			return
+		}
+	}
+
+	fn.Captures = append(fn.Captures, v)
+}
+
 func (fn *Function) AST() *ast.FunctionExpression {
 
 	x := fn.mod.Table.GetSymbol(fn)
@@ -207,7 +219,12 @@ func (fn *Function) SymbolName() string {
 
 	if fn.Self == nil {
 		return fn.symbol.SymbolName()
-	} else {
-		return fn.Self.SymbolName() + "::" + fn.name
 	}
+
+	// builtin types belong to no module, their methods are named within the module declaring them
+	if fn.Self.Module() == nil {
+		return fmt.Sprintf("%s::%s::%s", fn.symbol.mod.SymbolName(), fn.Self.Name(), fn.name)
+	}
+
+	return fn.Self.SymbolName() + "::" + fn.name
 }
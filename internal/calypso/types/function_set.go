@@ -94,6 +94,40 @@ func (s *FunctionSet) Find(sg *FunctionSignature, strict bool) *FunctionSet {
 	return set
 }
 
+// like `Find`, for a piped call, `x |> f(y)`. the piped value takes the label of the first parameter of each function it is matched against
+func (s *FunctionSet) FindPiped(sg *FunctionSignature) *FunctionSet {
+	if sg == nil || len(sg.Parameters) == 0 {
+		return nil
+	}
+
+	var set *FunctionSet
+
+	for _, fn := range s.Instances {
+		params := fn.Sg().Parameters
+
+		if len(params) == 0 {
+			continue
+		}
+
+		// the signature belongs to the call site, the labeled piped value is matched on a copy
+		piped := *sg.Parameters[0]
+		piped.ParamLabel = params[0].ParamLabel
+
+		labeled := *sg
+		labeled.Parameters = append([]*Var{&piped}, sg.Parameters[1:]...)
+
+		if s.Compare(&labeled, fn.Sg(), false) {
+			if set == nil {
+				set = NewFunctionSet(fn)
+			} else {
+				set.Instances = append(set.Instances, fn)
+			}
+		}
+	}
+
+	return set
+}
+
 /*
 This method is called ona function set generated by the `Find` function to pick the best option, take the signatures below
 
@@ -12,7 +12,6 @@ func Instantiate(t Type, ctx Specialization) Type {
 
 	// Non Generic Type, No Specialization Needed
 	if !IsGeneric(t) {
-		fmt.Println(t)
 		return t
 	}
 
@@ -54,6 +53,12 @@ func Instantiate(t Type, ctx Specialization) Type {
 		uT := Instantiate(cT, ctx) // Instantiate Type with Specialization Map
 		out = NewPointer(uT)       // Create new pointer with specialized type
 		return out                 // return updated pointer
+	case *Tuple:
+		elements := []Type{}
+		for _, e := range t.Elements {
+			elements = append(elements, Instantiate(e, ctx))
+		}
+		return NewTuple(elements)
 	default:
 		// unimplemented instantiation
 		panic(fmt.Sprintf("cannot instantiate type %s", t))
@@ -158,20 +158,3 @@ func (s *Scope) String() string {
 func (s *Scope) IsEmpty() bool {
 	return len(s.symbols) == 0
 }
-
-func (s *Scope) DebugPrintChildrenScopes() {
-
-	for _, symbol := range s.symbols {
-		definition := AsDefined(symbol.Type())
-
-		if definition == nil {
-			continue
-		}
-
-		if definition.scope.IsEmpty() {
-			continue
-		}
-
-		fmt.Println(definition.scope)
-	}
-}
@@ -1,7 +1,5 @@
 package types
 
-import "fmt"
-
 type SpecializedType struct {
 	Bounds     TypeList
 	Spec       Specialization
@@ -98,7 +96,6 @@ func makeBounds(params TypeParams, ctx Specialization) TypeList {
 		arg, ok := ctx[p]
 
 		if !ok {
-			fmt.Println("DEBUG - Unspecialized TypeParameter")
 			return nil
 		}
 
@@ -5,12 +5,16 @@ import "github.com/mantton/calypso/internal/calypso/ast"
 type SymbolTable struct {
 	Symbols map[Symbol]ast.Node // This links symbols to their corresponding nodes
 	Nodes   map[ast.Node]Type   // this links nodes to their corresponding types
+
+	Closures map[*ast.FunctionExpression]*Function // this links closures to their functions, as the type of a closure may be replaced by the type it is assigned to
 }
 
 func NewSymbolTable() *SymbolTable {
 	return &SymbolTable{
 		Symbols: make(map[Symbol]ast.Node),
 		Nodes:   make(map[ast.Node]Type),
+
+		Closures: make(map[*ast.FunctionExpression]*Function),
 	}
 }
 
@@ -29,3 +33,11 @@ func (t *SymbolTable) SetNodeType(n ast.Node, typ Type) {
 func (t *SymbolTable) GetNodeType(n ast.Node) Type {
 	return t.Nodes[n]
 }
+
+func (t *SymbolTable) SetClosure(n *ast.FunctionExpression, fn *Function) {
+	t.Closures[n] = fn
+}
+
+func (t *SymbolTable) GetClosure(n *ast.FunctionExpression) *Function {
+	return t.Closures[n]
+}
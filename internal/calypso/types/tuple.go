@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A fixed sequence of two or more values, `(int, bool)`. The elements of a tuple are the fields of its underlying struct, named by their index
+type Tuple struct {
+	Elements   []Type
+	underlying *Struct
+}
+
+func NewTuple(elements []Type) *Tuple {
+	fields := []*Var{}
+
+	for i, e := range elements {
+		fields = append(fields, NewVar(strconv.Itoa(i), e, nil))
+	}
+
+	return &Tuple{
+		Elements:   elements,
+		underlying: NewStruct(fields),
+	}
+}
+
+func (t *Tuple) Parent() Type { return t.underlying }
+
+func (t *Tuple) String() string {
+	elements := []string{}
+
+	for _, e := range t.Elements {
+		elements = append(elements, e.String())
+	}
+
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+func IsTuple(t Type) bool {
+	_, ok := ResolveAliases(t).(*Tuple)
+	return ok
+}
+
+func validateTupleTypes(expected *Tuple, p Type) (Type, error) {
+	provided, ok := p.(*Tuple)
+
+	if !ok || len(expected.Elements) != len(provided.Elements) {
+		return nil, fmt.Errorf("expected `%s`, received `%s`", expected, p)
+	}
+
+	// literal elements take the type of their counterpart
+	elements := []Type{}
+	changed := false
+
+	for i, e := range expected.Elements {
+		t, err := Validate(e, provided.Elements[i])
+
+		if err != nil {
+			return nil, fmt.Errorf("expected `%s`, received `%s`", expected, p)
+		}
+
+		changed = changed || t != e
+		elements = append(elements, t)
+	}
+
+	if !changed {
+		return expected, nil
+	}
+
+	return NewTuple(elements), nil
+}
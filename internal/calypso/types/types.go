@@ -54,6 +54,14 @@ func IsGeneric(t Type) bool {
 		}
 
 		return IsGeneric(t.Sg())
+	case *Tuple:
+		for _, e := range t.Elements {
+			if IsGeneric(e) {
+				return true
+			}
+		}
+
+		return false
 	default:
 		return false
 	}
@@ -128,6 +136,14 @@ func ResolveSymbol(t Type, n string) (Symbol, Type) {
 		}
 		return sym, typ
 
+	case *Tuple:
+		field := a.underlying.FindField(n)
+
+		if field == nil {
+			return nil, nil
+		}
+
+		return field, field.Type()
 	case *Module:
 		field := a.Scope.ResolveInCurrent(n)
 
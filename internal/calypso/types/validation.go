@@ -5,8 +5,6 @@ import (
 )
 
 func Validate(expected Type, provided Type) (Type, error) {
-	fmt.Printf("\t[VALIDATOR] Validating `%s`(provided) || `%s`(expected)\n", provided, expected)
-
 	if expected == provided {
 		return expected, nil
 	}
@@ -36,6 +34,8 @@ func Validate(expected Type, provided Type) (Type, error) {
 		return validateDefinedType(expected, provided)
 	case *SpecializedType:
 		return validateSpecializedType(expected, provided)
+	case *Tuple:
+		return validateTupleTypes(expected, provided)
 	default:
 		panic(fmt.Errorf("unhanled validation case: %T", expected))
 	}
@@ -45,7 +45,7 @@ func validateDefinedType(expected *DefinedType, provided Type) (Type, error) {
 	switch provided := provided.(type) {
 	case *DefinedType:
 		if p, ok := expected.Parent().(*Basic); ok {
-			return validateBasicTypes(p, provided.Parent(), expected)
+			return validateBasicTypes(p, provided, expected)
 		}
 
 		return nil, fmt.Errorf("expected `%s`, received `%s`", expected, provided)
@@ -72,9 +72,9 @@ func validateBasicTypes(expected *Basic, p Type, e Type) (Type, error) {
 	} else if IsGroupLiteral(expected) {
 		switch {
 		case expected.Literal == IntegerLiteral && IsNumeric(provided):
-			return provided, nil
+			return p, nil
 		case expected.Literal == FloatLiteral && IsFloatingPoint(provided):
-			return provided, nil
+			return p, nil
 		}
 	}
 